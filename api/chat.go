@@ -6,6 +6,7 @@ import (
 
 	"ai-gozero-agent/api/internal/config"
 	"ai-gozero-agent/api/internal/handler"
+	"ai-gozero-agent/api/internal/logic"
 	"ai-gozero-agent/api/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/conf"
@@ -25,6 +26,8 @@ func main() {
 
 	ctx := svc.NewServiceContext(c)
 	handler.RegisterHandlers(server, ctx)
+	// 知识库导入任务由本实例的 worker 在后台处理
+	ctx.IngestJobs.Start(c.Ingest.Workers, logic.IngestKnowledge(ctx))
 
 	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
 	server.Start()
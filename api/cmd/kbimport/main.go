@@ -0,0 +1,143 @@
+// kbimport 从目录、ZIP/tar 归档或导入清单批量导入知识库：直接向 Redis 导入队列创建任务，由api服务的 worker 处理。
+//
+//	kbimport -f etc/chat.yaml [-manifest manifest.json] [-collection go-core] [-topic gc] [-difficulty senior] [-tags go,gc] [-wait] <目录或归档>
+//
+// 只指定 -manifest 时，清单中的 path 相对清单所在目录，url 条目由本命令下载后入队
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"ai-gozero-agent/api/internal/config"
+	"ai-gozero-agent/api/internal/importer"
+	"ai-gozero-agent/api/internal/svc"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+var (
+	configFile   = flag.String("f", "etc/chat.yaml", "the config file")
+	manifestFile = flag.String("manifest", "", "导入清单，默认使用目录或归档中的 manifest.json")
+	collection   = flag.String("collection", "", "清单未指定集合时写入的知识库集合，默认 default")
+	topic        = flag.String("topic", "", "清单未指定主题时的主题")
+	difficulty   = flag.String("difficulty", "", "清单未指定难度时的难度：junior、middle、senior")
+	language     = flag.String("language", "", "清单未指定语言时的语言，为空时按内容识别")
+	tags         = flag.String("tags", "", "追加到所有文件的标签，逗号分隔")
+	wait         = flag.Bool("wait", false, "等待所有任务结束后输出汇总")
+	interval     = flag.Duration("interval", 3*time.Second, "-wait 时查询任务状态的间隔")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [目录或归档]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 || (flag.NArg() == 0 && *manifestFile == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", c.Redis.Host, c.Redis.Port),
+		Password: c.Redis.Password,
+		DB:       c.Redis.DB,
+	})
+	queue := svc.NewIngestQueue(rdb, c.Ingest)
+	im := importer.New(queue, c.MCP.MaxFileSize)
+	im.MaxFiles = c.Ingest.MaxBatchFiles
+	im.MaxBytes = c.Ingest.MaxBatchBytes
+	im.Defaults = svc.KnowledgeMeta{
+		Collection: *collection,
+		Topic:      *topic,
+		Difficulty: *difficulty,
+		Language:   *language,
+		Tags:       svc.ParseTags(*tags),
+	}
+	im.HTTPClient = &http.Client{Timeout: 5 * time.Minute}
+
+	batch, err := run(ctx, im)
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Printf("批次 %s：共 %d 个文件，入队 %d 个\n", batch.ID, len(batch.Items), len(batch.Items)-batch.Summary().Rejected)
+	for _, item := range batch.Items {
+		if item.Status == svc.ItemRejected {
+			fmt.Printf("  未入队 %s: %s\n", item.Path, item.Error)
+		}
+	}
+
+	if *wait {
+		for !batch.Summary().Done() {
+			s := batch.Summary()
+			fmt.Printf("排队 %d，执行中 %d，成功 %d，失败 %d\n", s.Queued, s.Running, s.Succeeded, s.Failed)
+			select {
+			case <-ctx.Done():
+				fatal(fmt.Errorf("已中断，任务仍在后台执行，可通过批次ID查询: %s", batch.ID))
+			case <-time.After(*interval):
+			}
+			if batch, err = queue.GetBatch(ctx, batch.ID); err != nil {
+				fatal(err)
+			}
+		}
+		for _, item := range batch.Items {
+			if item.Status == svc.JobFailed || item.Status == svc.JobCancelled {
+				fmt.Printf("  %s %s: %s\n", item.Status, item.Path, item.Error)
+			}
+		}
+	}
+
+	s := batch.Summary()
+	fmt.Printf("汇总：成功 %d，失败 %d，取消 %d，未入队 %d，未结束 %d\n", s.Succeeded, s.Failed, s.Cancelled, s.Rejected, s.Queued+s.Running)
+	if s.Rejected+s.Failed+s.Cancelled > 0 {
+		os.Exit(1)
+	}
+}
+
+// run 按参数导入目录、归档或只导入清单
+func run(ctx context.Context, im *importer.Importer) (*svc.ImportBatch, error) {
+	var manifest *importer.Manifest
+	if *manifestFile != "" {
+		m, err := importer.LoadManifestFile(*manifestFile)
+		if err != nil {
+			return nil, err
+		}
+		manifest = m
+		if flag.NArg() == 0 {
+			return im.Dir(ctx, filepath.Dir(*manifestFile), manifest)
+		}
+	}
+
+	src := flag.Arg(0)
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return im.Dir(ctx, src, manifest)
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return im.Archive(ctx, f, info.Size(), manifest)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,171 @@
+// kbsnapshot 导出或导入知识库快照（JSONL，可gzip压缩），用于在环境之间迁移整理好的知识库。
+//
+//	kbsnapshot -f etc/chat.yaml export [-collections go-core] [-filter "topic=gc"] [-embeddings] -o knowledge.jsonl.gz
+//	kbsnapshot -f etc/chat.yaml import [-reembed] knowledge.jsonl.gz
+//
+// 导入时快照不含向量、向量模型与当前配置不同或向量维度与已有知识不同时重新生成向量；已存在的知识块跳过
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"ai-gozero-agent/api/internal/config"
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/mcp/knowledgefilter"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+var configFile = flag.String("f", "etc/chat.yaml", "the config file")
+
+func main() {
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "usage: %s [-f config] export [flags] -o <快照文件>\n", os.Args[0])
+		fmt.Fprintf(out, "       %s [-f config] import [flags] <快照文件>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// 与api服务使用同一向量模型配置，ollama 的 ApiKey 留空即可
+	openaiConf := openai.DefaultConfig(c.OpenAI.ApiKey)
+	openaiConf.BaseURL = c.OpenAI.BaseURL
+	vs, err := svc.NewVectorStore(c.VectorDB, openai.NewClientWithConfig(openaiConf))
+	if err != nil {
+		fatal(err)
+	}
+	defer vs.Pool.Close()
+
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "export":
+		err = export(ctx, vs, args)
+	case "import":
+		err = load(ctx, vs, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func export(ctx context.Context, vs *svc.VectorStore, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "", "快照文件，以 .gz 结尾时gzip压缩")
+	collections := fs.String("collections", "", "导出的集合，逗号分隔，为空时导出全部集合")
+	filterExpr := fs.String("filter", "", "元数据过滤表达式，如 \"topic=gc, difficulty>=middle\"")
+	embeddings := fs.Bool("embeddings", false, "导出向量，导入环境使用相同向量模型时可免去重新生成")
+	fs.Parse(args)
+	if *output == "" || fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	names, err := svc.ParseCollections(*collections)
+	if err != nil {
+		return err
+	}
+	filter, err := knowledgefilter.Parse(*filterExpr)
+	if err != nil {
+		return err
+	}
+	filter = knowledgefilter.In("collection", names...).And(filter)
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = f
+	var gz *gzip.Writer
+	if strings.HasSuffix(*output, ".gz") {
+		gz = gzip.NewWriter(f)
+		w = gz
+	}
+	h, err := vs.ExportKnowledge(ctx, w, filter, *embeddings)
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(*output)
+		return err
+	}
+
+	fmt.Printf("已导出 %d 个文档、%d 个知识块到 %s（向量模型 %s", h.Documents, h.Chunks, *output, h.EmbeddingModel)
+	if h.Embeddings {
+		fmt.Printf("，%d 维", h.Dimension)
+	} else {
+		fmt.Print("，不含向量")
+	}
+	fmt.Println("）")
+	return nil
+}
+
+func load(ctx context.Context, vs *svc.VectorStore, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	reembed := fs.Bool("reembed", false, "忽略快照中的向量，全部重新生成")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	progressed := false
+	res, err := vs.ImportKnowledge(ctx, f, svc.SnapshotImportOptions{
+		Reembed: *reembed,
+		Progress: func(done, total int) {
+			if done%100 == 0 || done == total {
+				fmt.Printf("\r已处理 %d/%d", done, total)
+				progressed = true
+			}
+		},
+	})
+	if progressed {
+		fmt.Println()
+	}
+	if res != nil {
+		if res.Reembed != "" {
+			fmt.Printf("重新生成向量（%s）\n", res.Reembed)
+		}
+		fmt.Printf("导入 %d 个知识块，跳过已存在的 %d 个\n", res.Imported, res.Skipped)
+	}
+	if errors.Is(err, context.Canceled) {
+		return errors.New("已中断，重新导入同一快照会跳过已导入的知识块")
+	}
+	if errors.Is(err, svc.ErrSnapshotIncomplete) && res != nil {
+		return fmt.Errorf("%v\n快照文件不完整，已部分导入 %d 个知识块；请重新导出完整快照后再次导入，已导入的知识块会跳过", err, res.Imported)
+	}
+	return err
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -1,6 +1,10 @@
 package config
 
-import "github.com/zeromicro/go-zero/rest"
+import (
+	"time"
+
+	"github.com/zeromicro/go-zero/rest"
+)
 
 type Config struct {
 	rest.RestConf
@@ -18,12 +22,90 @@ type Config struct {
 		PresencePenalty  float32
 		Seed             *int
 	}
+	Profiles      map[string]ModelProfile `json:",optional"` // 面试状态或任务 -> 模型参数，未配置的字段沿用OpenAI段
+	LLM           LLMConfig
+	Reasoning     ReasoningConfig
+	Usage         UsageConfig
+	Tools         ToolsConfig
 	VectorDB      VectorDBConfig
 	UniPDFLicense string
 	MCP           struct {
-		Endpoint string
+		Backend     string `json:",default=remote,options=remote|local"` // 文档解析方式：remote 通过MCP服务（gRPC），local 在api进程内解析（不支持OCR）
+		Endpoint    string `json:",optional"`                            // MCP服务地址，remote 时必填
+		ChunkSize   int    `json:",default=262144"`                      // 上传分块大小，需小于gRPC单条消息上限（4MB）
+		MaxFileSize int64  `json:",default=20971520"`                    // 上传文件大小上限，需与mcp服务 MaxFileSize 一致
+		Layout      string `json:",default=text,options=text|markdown"`  // 解析版式，markdown 保留PDF中的标题、列表、表格与代码块
+	}
+	Sandbox struct {
+		Endpoint string `json:",optional"` // 代码运行沙箱服务地址，为空时不提供 run_go_snippet 工具
 	}
-	Redis Redis
+	Redis     Redis
+	Ingest    IngestConfig
+	Stream    StreamConfig
+	WebSocket WebSocketConfig
+}
+
+// ModelProfile 模型参数配置，字段为空时沿用OpenAI段的默认值
+type ModelProfile struct {
+	Model            string   `json:",optional"` // 模型名或提供方模型别名
+	MaxTokens        int      `json:",optional"`
+	Temperature      *float32 `json:",optional"`
+	TopP             *float32 `json:",optional"`
+	FrequencyPenalty *float32 `json:",optional"`
+	PresencePenalty  *float32 `json:",optional"`
+}
+
+// ReasoningConfig 推理模型 <think> 内容处理，推理内容不会推送给候选人，也不计入历史与状态评估
+type ReasoningConfig struct {
+	Emit             bool          `json:",default=false"` // 是否以 reasoning 事件推送推理内容
+	InterviewerToken string        `json:",optional"`      // 携带该令牌（X-Interviewer-Token 头）的连接可接收推理内容
+	TicketTTL        time.Duration `json:",default=1m"`    // 面试官票据有效期，供无法设置请求头的EventSource与WebSocket连接使用
+}
+
+// UsageConfig token用量统计与会话预算
+type UsageConfig struct {
+	SessionTokenBudget int64         `json:",default=0"`    // 单场面试token预算（0为不限制），用尽后结束面试
+	WrapUpRatio        float64       `json:",default=0.9"`  // 用量达到预算的该比例时进入评估总结阶段
+	DailyRetention     time.Duration `json:",default=720h"` // Redis中按天汇总的保留时长
+}
+
+// ToolsConfig 工具调用配置，需模型支持 function calling
+type ToolsConfig struct {
+	Enabled   bool     `json:",default=false"`
+	MaxRounds int      `json:",default=5"` // 单轮回答最多的工具调用轮数，超过后要求模型直接作答
+	Allow     []string `json:",optional"`  // 启用的内置工具，为空时启用全部
+
+	MCPServers []MCPServerConfig `json:",optional"` // 外部MCP服务，其工具以 {Name}__{工具名} 暴露给模型
+}
+
+// MCPServerConfig 外部MCP服务，Command（stdio）与 URL（streamable HTTP）二选一
+type MCPServerConfig struct {
+	Name    string
+	Command string            `json:",optional"` // 启动命令
+	Args    []string          `json:",optional"`
+	Env     []string          `json:",optional"`    // 追加的环境变量，KEY=VALUE
+	URL     string            `json:",optional"`    // HTTP端点
+	Headers map[string]string `json:",optional"`    // 附加请求头，如 Authorization
+	Allow   []string          `json:",optional"`    // 暴露给模型的工具，为空时全部
+	Timeout time.Duration     `json:",default=30s"` // 单次请求超时
+}
+
+// LLMConfig 大模型提供方配置，按顺序故障转移；未配置时使用OpenAI段作为唯一提供方
+type LLMConfig struct {
+	FailureThreshold int           `json:",default=1"`   // 连续失败多少次后熔断
+	Cooldown         time.Duration `json:",default=30s"` // 熔断时长，期间优先跳过该提供方
+	Providers        []LLMProvider `json:",optional"`
+}
+
+type LLMProvider struct {
+	Name    string
+	Type    string            `json:",default=openai,options=openai|ollama|dashscope"`
+	BaseURL string            `json:",optional"`
+	ApiKey  string            `json:",optional"`
+	Model   string            `json:",optional"` // 默认模型
+	Models  map[string]string `json:",optional"` // 模型别名 -> 实际模型名
+
+	IncludeUsage bool `json:",default=true"` // openai类型：流式请求携带 stream_options.include_usage，不支持的服务可关闭
 }
 
 // VectorDBConfig 向量数据库配置
@@ -43,6 +125,16 @@ type Knowledge struct {
 	MaxChunkSize     int
 	TopK             int
 	MaxContextLength int
+
+	// 会话附件（简历、设计文档等）存入会话级临时知识库
+	SessionTopK           int           `json:",default=3"`    // 每轮检索的附件片段数量
+	AttachmentInlineLimit int           `json:",default=2047"` // 附件不超过该长度时直接拼接进用户消息，超过时存入会话级知识库
+	SessionTTL            time.Duration `json:",default=24h"`  // 未正常结束的会话附件保留时长
+
+	// 知识库集合：会话未通过 collections 参数指定集合时按面试状态选择，均未配置时检索全部集合
+	Collections      []string            `json:",optional"` // 状态未单独配置时检索的集合
+	StateCollections map[string][]string `json:",optional"` // 面试状态 -> 检索的集合，如 question: [go-core]
+	StateFilters     map[string]string   `json:",optional"` // 面试状态 -> 元数据过滤表达式，如 question: "topic=concurrency, difficulty=senior"
 }
 
 type Redis struct {
@@ -51,3 +143,30 @@ type Redis struct {
 	Password string
 	DB       int
 }
+
+// IngestConfig 知识库导入任务队列（Redis），上传接口入队后立即返回任务ID
+type IngestConfig struct {
+	Workers     int           `json:",default=2"`           // 本实例的导入worker数量，0 表示只入队，由其他实例处理
+	MaxAttempts int           `json:",default=3"`           // 单个任务最多执行次数（含worker中断后被重新认领）
+	StaleAfter  time.Duration `json:",default=5m"`          // 执行中的任务超过该时长没有心跳时由其他worker重新认领
+	JobTTL      time.Duration `json:",default=168h"`        // 任务状态与上传文件的保留时长
+	FileDir     string        `json:",default=data/ingest"` // 上传文件的保存目录，Redis 中只记录文件名；多实例部署时需共享该目录
+
+	MaxBatchFiles int   `json:",default=1000"`       // 单个批量导入最多的文件数（含清单中的URL）
+	MaxBatchBytes int64 `json:",default=1073741824"` // 单个批量导入解压后的文件总大小上限
+}
+
+// StreamConfig 生成事件缓存配置（断线续传）
+type StreamConfig struct {
+	TTL               time.Duration `json:",default=10m"`   // 事件流保留时长
+	MaxLen            int64         `json:",default=10000"` // 单轮事件流最大长度
+	BlockTimeout      time.Duration `json:",default=15s"`   // 订阅阻塞读取超时
+	GenerationTimeout time.Duration `json:",default=5m"`    // 单轮生成最长时间（与客户端连接解耦）
+}
+
+// WebSocketConfig WebSocket 会话配置
+type WebSocketConfig struct {
+	HeartbeatInterval time.Duration `json:",default=30s"` // 客户端心跳间隔，超过两倍间隔未收到消息则断开
+	TimeLimit         time.Duration `json:",default=0s"`  // 面试时长限制，到时推送提示并进入评估阶段（0为不限制）
+	IdleReminder      time.Duration `json:",default=0s"`  // 候选人长时间未作答时推送提醒（0为不提醒）
+}
@@ -3,14 +3,17 @@ package handler
 import (
 	"ai-gozero-agent/api/internal/utils"
 	"context"
-	"fmt"
+	"errors"
 	"github.com/zeromicro/go-zero/core/logx"
+	"math"
+	"mime/multipart"
 	"net/http"
-	"strings"
 
 	"ai-gozero-agent/api/internal/logic"
 	"ai-gozero-agent/api/internal/svc"
 	"ai-gozero-agent/api/internal/types"
+	"ai-gozero-agent/api/sse"
+	"ai-gozero-agent/mcp/extractor"
 	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
@@ -18,104 +21,144 @@ import (
 func ChatHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// 设置SSE响应头
-		setSSEHeader(w)
-		flusher, _ := w.(http.Flusher)
+		sw := sse.NewWriter(w)
+		sw.SetHeader()
 
 		// 处理请求
 		var req types.InterViewAPPChatReq
 		if err := httpx.Parse(r, &req); err != nil {
-			sendSSEError(w, flusher, err.Error())
+			sw.Error(sse.ErrCodeBadRequest, err.Error())
 			return
 		}
 
-		// 处理PDF文件（如果有）
-		var pdfContent string
+		// 断线重连：携带Last-Event-ID时续传上一轮生成，不再处理新消息
+		if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+			resumeEvents(r, svcCtx, sw, req.ChatId, lastEventID)
+			return
+		}
+
+		// 处理附件（如果有）
+		var pdfContent, filename string
 		if file, header, err := r.FormFile("file"); err == nil {
 			defer file.Close()
 
-			// 验证文件类型
-			if header.Header.Get("Content-Type") != "application/pdf" {
-				http.Error(w, "invalid file type", http.StatusBadRequest)
+			// 按内容识别文件格式，不信任客户端声明的类型
+			if _, err := extractor.Detect(file, header.Size); err != nil {
+				sw.Error(sse.ErrCodeBadRequest, "invalid file type")
 				return
 			}
 
-			// 使用UniPDF提取文本
-			//if content, err := utils.ExtractPDFText(file); err == nil {
-			//	pdfContent = content
-			//} else {
-			//	logx.Error("PDF content error", err)
-			//}
-
-			// 提取文本
-			if content, err := svcCtx.PdfClient.ExtractFile(file, header.Filename); err == nil {
+			// 提取文本，上传与解析进度以 progress 事件推送
+			content, err := extractWithProgress(r, svcCtx, sw, file, header)
+			if errors.Is(err, svc.ErrFileTooLarge) {
+				sw.Error(sse.ErrCodeBadRequest, "file too large")
+				return
+			}
+			if err == nil {
 				pdfContent = content
+				filename = header.Filename
 			} else {
+				// 附件无法使用（如扫描件未识别出文本）时告知客户端，本轮对话不带附件继续
 				logx.Errorf("get pdf content failed, err:%v", err)
+				_ = sw.Write(sse.Progress(sse.ProgressData{Stage: sse.ProgressExtract, Percent: 100, Error: err.Error()}))
 			}
 		}
 
-		// 4.拼接消息
-		req.Message = utils.CombineMessages(req.Message, pdfContent)
-		fmt.Println("req.Message+++++66666", req.Message)
+		// 4.拼接消息（超过内联长度的附件在取得生成名额后存入会话级知识库）
+		knowledgeCfg := svcCtx.Config.VectorDB.Knowledge
+		req.Message = utils.CombineMessages(req.Message, filename, pdfContent, knowledgeCfg.AttachmentInlineLimit)
 
 		// 创建取消上下文
 		ctx, cancel := context.WithCancel(r.Context())
 		defer cancel() // 确保资源释放
 
-		l := logic.NewChatLogic(ctx, svcCtx)
-		respChan, err := l.Chat(&req)
+		l := logic.NewChatLogic(ctx, svcCtx).
+			WithReasoning(isInterviewer(r, svcCtx, req.ChatId)).
+			WithAttachment(filename, pdfContent)
+		events, err := l.Chat(&req)
+		if errors.Is(err, svc.ErrInvalidCollection) {
+			sw.Error(sse.ErrCodeBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, logic.ErrGenerationBusy) {
+			sw.Error(sse.ErrCodeBusy, err.Error())
+			return
+		}
 		if err != nil {
-			sendSSEError(w, flusher, err.Error())
+			sw.Error(sse.ErrCodeInternal, err.Error())
 			return
 		}
 
-		// 处理流式响应
-		for {
-			select {
-			case <-ctx.Done():
+		streamEvents(ctx, sw, events)
+	}
+}
+
+// streamEvents 将事件逐个写给客户端，直到事件结束或客户端断开
+func streamEvents(ctx context.Context, sw *sse.Writer, events <-chan *sse.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := sw.Write(e); err != nil {
+				logx.Errorf("write sse event failed, err:%v", err)
 				return
-			case resp, ok := <-respChan:
-				if !ok {
-					_, err := fmt.Fprint(w, "event: end\ndata: {}\n\n")
-					if err != nil {
-						return
-					}
-					// 结束标记
-					flusher.Flush()
-					return
-				}
-				// 加个处理符合前端markdown格式
-				safeContent := strings.ReplaceAll(resp.Content, "\n", "\\n")
-				safeContent = strings.ReplaceAll(safeContent, "\r", "\\r")
-				// 直接输出内容，不加JSON包装
-				_, err := fmt.Fprintf(w, "data: %s\n\n", safeContent)
-				if err != nil {
-					return
-				}
-				flusher.Flush()
-
-				if resp.IsLast {
-					return
-				}
 			}
 		}
 	}
 }
 
-// setsSEHeader设置服务器推送事件(SSE)的响应头
-func setSSEHeader(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("cache-Control", "no-cache")
-	w.Header().Set("connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("x-Accel-Buffering", "no")
-	w.Header().Set("Transfer-Encoding", "chunked")
+// resumeEvents 从Last-Event-ID之后续传事件
+func resumeEvents(r *http.Request, svcCtx *svc.ServiceContext, sw *sse.Writer, chatId, lastEventID string) {
+	l := logic.NewChatLogic(r.Context(), svcCtx).WithReasoning(isInterviewer(r, svcCtx, chatId))
+	events, err := l.Resume(chatId, lastEventID)
+	if err != nil {
+		sw.Error(sse.ErrCodeExpired, err.Error())
+		return
+	}
+	streamEvents(r.Context(), sw, events)
 }
 
-func sendSSEError(w http.ResponseWriter, flusher http.Flusher, errMsg string) {
-	_, fprintf := fmt.Fprintf(w, "event: error\ndata: {\"error\":\"%s\"}\n\n", errMsg)
-	if fprintf != nil {
-		return
+// extractWithProgress 解析附件，上传（仅远程解析）与逐页解析进度变化时推送 progress 事件
+func extractWithProgress(r *http.Request, svcCtx *svc.ServiceContext, sw *sse.Writer, file multipart.File, header *multipart.FileHeader) (string, error) {
+	lastPercent := int64(-1)
+	onUpload := func(sent, total int64) {
+		if total <= 0 {
+			return
+		}
+		percent := min(sent*100/total, 100)
+		if percent == lastPercent {
+			return
+		}
+		lastPercent = percent
+		_ = sw.Write(sse.Progress(sse.ProgressData{Stage: sse.ProgressUpload, Percent: float64(percent)}))
+	}
+	onPage := func(e *svc.PageEvent) {
+		_ = sw.Write(sse.Progress(sse.ProgressData{
+			Stage:      sse.ProgressExtract,
+			Percent:    math.Round(e.Percent*10) / 10,
+			Page:       e.Page,
+			Pages:      e.Pages,
+			Error:      e.Error,
+			OCR:        e.OCR,
+			Confidence: math.Round(e.Confidence*100) / 100,
+		}))
+	}
+	res, err := svcCtx.Extractor.Extract(r.Context(), file, header.Filename, header.Size, svc.ExtractHooks{OnUpload: onUpload, OnPage: onPage})
+	if err != nil {
+		return "", err
+	}
+	return res.Content, nil
+}
+
+// isInterviewer 请求头携带面试官令牌，或查询参数携带该会话的面试官票据（EventSource与WebSocket无法设置请求头）
+func isInterviewer(r *http.Request, svcCtx *svc.ServiceContext, chatId string) bool {
+	if token := r.Header.Get("X-Interviewer-Token"); token != "" {
+		return svcCtx.IsInterviewer(token)
 	}
-	flusher.Flush()
+	ticket := r.URL.Query().Get("ticket")
+	return ticket != "" && svcCtx.IsInterviewerTicket(chatId, ticket)
 }
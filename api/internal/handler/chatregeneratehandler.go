@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"ai-gozero-agent/api/internal/logic"
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"ai-gozero-agent/api/sse"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 重新生成最近一轮回答（SSE）
+func ChatRegenerateHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sw := sse.NewWriter(w)
+		sw.SetHeader()
+
+		var req types.ChatRegenerateReq
+		if err := httpx.Parse(r, &req); err != nil {
+			sw.Error(sse.ErrCodeBadRequest, err.Error())
+			return
+		}
+
+		l := logic.NewChatLogic(r.Context(), svcCtx).WithReasoning(isInterviewer(r, svcCtx, req.ChatId))
+		events, err := l.Regenerate(req.ChatId)
+		switch {
+		case errors.Is(err, logic.ErrGenerationBusy):
+			sw.Error(sse.ErrCodeBusy, err.Error())
+			return
+		case errors.Is(err, logic.ErrNothingToRegenerate):
+			sw.Error(sse.ErrCodeBadRequest, err.Error())
+			return
+		case err != nil:
+			sw.Error(sse.ErrCodeInternal, err.Error())
+			return
+		}
+
+		streamEvents(r.Context(), sw, events)
+	}
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"ai-gozero-agent/api/sse"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// SSE断线续传（兼容浏览器EventSource自动重连）
+func ChatResumeHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sw := sse.NewWriter(w)
+		sw.SetHeader()
+
+		var req types.InterViewAPPResumeReq
+		if err := httpx.Parse(r, &req); err != nil {
+			sw.Error(sse.ErrCodeBadRequest, err.Error())
+			return
+		}
+
+		resumeEvents(r, svcCtx, sw, req.ChatId, r.Header.Get("Last-Event-ID"))
+	}
+}
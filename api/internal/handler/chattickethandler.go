@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"ai-gozero-agent/api/internal/logic"
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 签发面试官票据（需携带 X-Interviewer-Token 头）
+func ChatTicketHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ChatTicketReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewChatTicketLogic(r.Context(), svcCtx)
+		resp, err := l.ChatTicket(&req, r.Header.Get("X-Interviewer-Token"))
+		if errors.Is(err, logic.ErrNotInterviewer) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		} else if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
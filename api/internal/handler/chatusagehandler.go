@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"ai-gozero-agent/api/internal/logic"
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 会话token用量
+func ChatUsageHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ChatUsageReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewChatUsageLogic(r.Context(), svcCtx)
+		resp, err := l.ChatUsage(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
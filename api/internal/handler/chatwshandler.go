@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"ai-gozero-agent/api/internal/logic"
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocket双向面试会话
+func ChatWsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.InterViewAPPWsReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		collections, err := svc.ParseCollections(req.Collections)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		// 使用websocket.Server而非websocket.Handler，跨域与SSE接口保持一致不校验Origin
+		websocket.Server{
+			Handler: func(conn *websocket.Conn) {
+				l := logic.NewChatWsLogic(r.Context(), svcCtx, conn, req.ChatId, isInterviewer(r, svcCtx, req.ChatId))
+				l.Serve(collections)
+			},
+		}.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+
+	"ai-gozero-agent/api/internal/logic"
+	"ai-gozero-agent/api/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 知识库集合列表
+func KnowledgeCollectionsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewKnowledgeCollectionsLogic(r.Context(), svcCtx)
+		resp, err := l.KnowledgeCollections()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
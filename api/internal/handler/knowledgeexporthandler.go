@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"ai-gozero-agent/api/internal/logic"
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 导出知识库快照（gzip压缩的JSONL）
+func KnowledgeExportHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.KnowledgeExportReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewKnowledgeExportLogic(r.Context(), svcCtx)
+		if err := l.KnowledgeExport(&req, w); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"ai-gozero-agent/api/internal/importer"
+	"ai-gozero-agent/api/internal/logic"
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// KnowledgeImportHandler 批量导入归档：file 为 ZIP 或 tar（可gzip压缩）归档，可选 manifest 为导入清单（优先于归档内的 manifest.json）
+func KnowledgeImportHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.KnowledgeImportReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		defer file.Close()
+
+		var manifest *importer.Manifest
+		if mf, _, err := r.FormFile("manifest"); err == nil {
+			manifest, err = importer.LoadManifest(mf)
+			mf.Close()
+			if err != nil {
+				httpx.ErrorCtx(r.Context(), w, err)
+				return
+			}
+		}
+
+		l := logic.NewKnowledgeImportLogic(r.Context(), svcCtx)
+		resp, err := l.KnowledgeImport(&req, file, header.Size, manifest)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
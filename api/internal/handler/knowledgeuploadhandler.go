@@ -2,9 +2,8 @@ package handler
 
 import (
 	"ai-gozero-agent/api/internal/types"
-	"ai-gozero-agent/api/internal/utils"
-	"errors"
-	"fmt"
+	"ai-gozero-agent/mcp/extractor"
+	"io"
 	"net/http"
 
 	"ai-gozero-agent/api/internal/logic"
@@ -12,42 +11,40 @@ import (
 	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
-// KnowledgeUploadHandler 知识库上传
+// KnowledgeUploadHandler 知识库上传，创建导入任务后立即返回任务ID
 func KnowledgeUploadHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// 设置SSE响应头
-		setSSEHeader(w)
-		fmt.Println("进入上传知识库！！！")
+		var req types.KnowledgeUploadReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
 		// 获取文件
 		file, header, err := r.FormFile("file")
 		if err != nil {
-			httpx.Error(w, err)
+			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
 		defer file.Close()
-
-		// 验证PDF
-		if header.Header.Get("Content-Type") != "application/pdf" {
-			httpx.Error(w, errors.New("仅支持PDF文件"))
+		if header.Size > svcCtx.Config.MCP.MaxFileSize {
+			httpx.ErrorCtx(r.Context(), w, svc.ErrFileTooLarge)
 			return
 		}
 
-		// 提取文本
-		content, err := utils.ExtractPDFText(file)
+		// 按内容识别格式，不支持的文件不入队
+		if _, err := extractor.Detect(file, header.Size); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+		data, err := io.ReadAll(file)
 		if err != nil {
-			httpx.Error(w, err)
+			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
 
-		// 获取标题（使用文件名）
-		title := header.Filename
-		fmt.Println("标题：", title)
-
 		l := logic.NewKnowledgeUploadLogic(r.Context(), svcCtx)
-		resp, err := l.KnowledgeUpload(&types.KnowledgeUploadReq{
-			Title:   title,
-			Content: content,
-		})
+		resp, err := l.KnowledgeUpload(&req, header.Filename, data)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 		} else {
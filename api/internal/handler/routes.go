@@ -15,17 +15,107 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 	server.AddRoutes(
 		[]rest.Route{
 			{
-				// 知识库上传
+				// 知识库上传（创建导入任务）
 				Method:  http.MethodPost,
 				Path:    "/api/ai/knowledge/upload",
 				Handler: KnowledgeUploadHandler(serverCtx),
 			},
+			{
+				// 知识库导入任务状态
+				Method:  http.MethodGet,
+				Path:    "/api/ai/knowledge/job",
+				Handler: KnowledgeJobHandler(serverCtx),
+			},
+			{
+				// 重试失败或已取消的导入任务
+				Method:  http.MethodPost,
+				Path:    "/api/ai/knowledge/job/retry",
+				Handler: KnowledgeJobRetryHandler(serverCtx),
+			},
+			{
+				// 取消导入任务
+				Method:  http.MethodPost,
+				Path:    "/api/ai/knowledge/job/cancel",
+				Handler: KnowledgeJobCancelHandler(serverCtx),
+			},
 			{
 				// SSE流式接口
 				Method:  http.MethodPost,
 				Path:    "/api/ai/interview_app/chat/sse",
 				Handler: ChatHandler(serverCtx),
 			},
+			{
+				// SSE断线续传
+				Method:  http.MethodGet,
+				Path:    "/api/ai/interview_app/chat/resume",
+				Handler: ChatResumeHandler(serverCtx),
+			},
+			{
+				// WebSocket双向面试会话
+				Method:  http.MethodGet,
+				Path:    "/api/ai/interview_app/chat/ws",
+				Handler: ChatWsHandler(serverCtx),
+			},
+			{
+				// 取消进行中的生成
+				Method:  http.MethodPost,
+				Path:    "/api/ai/interview_app/chat/cancel",
+				Handler: ChatCancelHandler(serverCtx),
+			},
+			{
+				// 签发面试官票据（需携带 X-Interviewer-Token 头）
+				Method:  http.MethodPost,
+				Path:    "/api/ai/interview_app/chat/ticket",
+				Handler: ChatTicketHandler(serverCtx),
+			},
+			{
+				// 重新生成最近一轮回答（SSE）
+				Method:  http.MethodPost,
+				Path:    "/api/ai/interview_app/chat/regenerate",
+				Handler: ChatRegenerateHandler(serverCtx),
+			},
+			{
+				// 会话token用量
+				Method:  http.MethodGet,
+				Path:    "/api/ai/interview_app/chat/usage",
+				Handler: ChatUsageHandler(serverCtx),
+			},
+			{
+				// 每日token用量
+				Method:  http.MethodGet,
+				Path:    "/api/ai/usage/daily",
+				Handler: DailyUsageHandler(serverCtx),
+			},
+			{
+				// 批量导入状态汇总
+				Method:  http.MethodGet,
+				Path:    "/api/ai/knowledge/import/batch",
+				Handler: KnowledgeBatchHandler(serverCtx),
+			},
+			{
+				// 知识库集合列表
+				Method:  http.MethodGet,
+				Path:    "/api/ai/knowledge/collections",
+				Handler: KnowledgeCollectionsHandler(serverCtx),
+			},
+			{
+				// 导出知识库快照（gzip压缩的JSONL）
+				Method:  http.MethodGet,
+				Path:    "/api/ai/knowledge/export",
+				Handler: KnowledgeExportHandler(serverCtx),
+			},
+		},
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 批量导入ZIP/tar归档（每个文件创建一个导入任务）
+				Method:  http.MethodPost,
+				Path:    "/api/ai/knowledge/import",
+				Handler: KnowledgeImportHandler(serverCtx),
+			},
 		},
+		rest.WithMaxBytes(1073741824),
 	)
 }
@@ -0,0 +1,423 @@
+// Package importer 从目录、ZIP/tar 归档或清单中的URL批量导入知识库，每个文件创建一个导入任务，
+// 与单文件上传走同一条解析、分块与入库流程
+package importer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/mcp/extractor"
+)
+
+var (
+	// ErrUnsupportedArchive 不是 ZIP 或 tar（可gzip压缩）归档
+	ErrUnsupportedArchive = errors.New("仅支持 ZIP 与 tar（可gzip压缩）归档")
+	// ErrNoFiles 没有可导入的文件
+	ErrNoFiles = errors.New("没有可导入的文件")
+	// ErrURLNotAllowed 未配置 HTTPClient 时不下载清单中的URL
+	ErrURLNotAllowed = errors.New("不支持URL条目，请使用命令行导入")
+	// ErrBatchTooLarge 批次的文件数或文件总大小超过限制
+	ErrBatchTooLarge = errors.New("批量导入的文件数或文件总大小超过限制")
+)
+
+// maxManifestSize 归档中导入清单的大小上限
+const maxManifestSize = 1 << 20
+
+// Importer 批量创建导入任务
+type Importer struct {
+	q       *svc.IngestQueue
+	maxSize int64
+
+	Defaults   svc.KnowledgeMeta // 清单未指定时的元数据，标签追加到所有文件
+	HTTPClient *http.Client      // 下载清单中的URL，为nil时拒绝URL条目
+	MaxFiles   int               // 单个批次最多的文件数（含URL条目），为0时不限制
+	MaxBytes   int64             // 单个批次读取的文件总大小上限（解压后），为0时不限制
+}
+
+func New(q *svc.IngestQueue, maxSize int64) *Importer {
+	return &Importer{q: q, maxSize: maxSize}
+}
+
+// visitFunc 遍历到的文件，open 返回的内容只在回调期间有效
+type visitFunc func(name string, size int64, open func() (io.ReadCloser, error)) error
+
+// Dir 导入目录，m 为nil时使用目录下的 manifest.json（如有）
+func (im *Importer) Dir(ctx context.Context, root string, m *Manifest) (*svc.ImportBatch, error) {
+	if m == nil {
+		var err error
+		if m, err = LoadManifestFile(filepath.Join(root, ManifestName)); errors.Is(err, fs.ErrNotExist) {
+			m = &Manifest{}
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return im.run(ctx, m, "", func(visit visitFunc) error {
+		return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return visit(filepath.ToSlash(rel), info.Size(), func() (io.ReadCloser, error) { return os.Open(p) })
+		})
+	})
+}
+
+// Archive 导入 ZIP 或 tar（可gzip压缩）归档，m 为nil时使用归档根目录（或唯一的顶层目录）下的 manifest.json（如有）
+func (im *Importer) Archive(ctx context.Context, r io.ReaderAt, size int64, m *Manifest) (*svc.ImportBatch, error) {
+	walk, err := archiveWalker(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	base := ""
+	if m == nil {
+		if m, base, err = findManifest(walk); err != nil {
+			return nil, err
+		}
+	}
+	return im.run(ctx, m, base, walk)
+}
+
+// findManifest 查找归档中的 manifest.json，根目录优先，其次为唯一顶层目录下的清单；没有时返回空清单
+func findManifest(walk func(visitFunc) error) (*Manifest, string, error) {
+	found := make(map[string][]byte)
+	err := walk(func(name string, size int64, open func() (io.ReadCloser, error)) error {
+		name = cleanPath(name)
+		if path.Base(name) != ManifestName || strings.Count(name, "/") > 1 || size > maxManifestSize {
+			return nil
+		}
+		f, err := open()
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		found[name], err = io.ReadAll(f)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := ManifestName
+	if _, ok := found[name]; !ok {
+		if len(found) != 1 {
+			return &Manifest{}, "", nil
+		}
+		for name = range found {
+		}
+	}
+	m, err := LoadManifest(bytes.NewReader(found[name]))
+	if err != nil {
+		return nil, "", err
+	}
+	if base := path.Dir(name); base != "." {
+		return m, base, nil
+	}
+	return m, "", nil
+}
+
+// archiveWalker 按文件头识别归档格式
+func archiveWalker(r io.ReaderAt, size int64) (func(visitFunc) error, error) {
+	head := make([]byte, 512)
+	n, _ := r.ReadAt(head, 0)
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")) || bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			return nil, fmt.Errorf("读取ZIP归档失败: %w", err)
+		}
+		return func(visit visitFunc) error {
+			for _, f := range zr.File {
+				if !f.Mode().IsRegular() {
+					continue
+				}
+				if err := visit(f.Name, int64(f.UncompressedSize64), f.Open); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return func(visit visitFunc) error {
+			gz, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+			if err != nil {
+				return fmt.Errorf("读取gzip归档失败: %w", err)
+			}
+			defer gz.Close()
+			return walkTar(gz, visit)
+		}, nil
+	case len(head) > 262 && string(head[257:262]) == "ustar":
+		return func(visit visitFunc) error {
+			return walkTar(io.NewSectionReader(r, 0, size), visit)
+		}, nil
+	}
+	return nil, ErrUnsupportedArchive
+}
+
+func walkTar(r io.Reader, visit visitFunc) error {
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取tar归档失败: %w", err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := visit(h.Name, h.Size, func() (io.ReadCloser, error) { return io.NopCloser(tr), nil }); err != nil {
+			return err
+		}
+	}
+}
+
+// selection 按清单筛选归档中的文件
+type selection struct {
+	base    string
+	entries map[string]*Entry // 清单列出的文件，为nil时选择除隐藏文件外的全部文件
+	found   map[*Entry]bool
+}
+
+func newSelection(m *Manifest, base string) *selection {
+	return &selection{base: base, entries: m.entries(base), found: make(map[*Entry]bool)}
+}
+
+// pick 返回文件对应的条目，不导入（清单、未列出、重复或隐藏文件）时返回nil
+func (s *selection) pick(name string) *Entry {
+	name = cleanPath(name)
+	if name == path.Join(s.base, ManifestName) {
+		return nil
+	}
+	if s.entries == nil {
+		if hidden(name) {
+			return nil
+		}
+		return &Entry{Path: strings.TrimPrefix(name, s.base+"/")}
+	}
+	e := s.entries[name]
+	if e == nil || s.found[e] {
+		return nil
+	}
+	s.found[e] = true
+	return e
+}
+
+// checkLimits 按文件头统计批次的文件数与总大小，超过限制时整批拒绝，不创建任何任务；
+// 超限后立即停止遍历，避免完整解压压缩炸弹
+func (im *Importer) checkLimits(ctx context.Context, m *Manifest, base string, walk func(visitFunc) error) error {
+	if im.MaxFiles <= 0 && im.MaxBytes <= 0 {
+		return nil
+	}
+	files, total := 0, int64(0)
+	for _, e := range m.Files {
+		if e.URL != "" {
+			files++
+		}
+	}
+	sel := newSelection(m, base)
+	check := func() error {
+		if im.MaxFiles > 0 && files > im.MaxFiles {
+			return fmt.Errorf("%w：文件数超过 %d", ErrBatchTooLarge, im.MaxFiles)
+		}
+		if im.MaxBytes > 0 && total > im.MaxBytes {
+			return fmt.Errorf("%w：文件总大小超过 %d 字节", ErrBatchTooLarge, im.MaxBytes)
+		}
+		return nil
+	}
+	if err := check(); err != nil {
+		return err
+	}
+	return walk(func(name string, size int64, open func() (io.ReadCloser, error)) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if sel.pick(name) == nil {
+			return nil
+		}
+		files++
+		if size <= im.maxSize { // 超过单文件大小限制的文件不读取
+			total += size
+		}
+		return check()
+	})
+}
+
+// run 为清单中列出的文件（清单没有列出文件时为全部文件）创建导入任务并保存批次；
+// 单个文件读取失败、格式不支持或超过大小限制时记为 rejected，不影响其他文件；文件数或总大小超过批次限制时整批拒绝
+func (im *Importer) run(ctx context.Context, m *Manifest, base string, walk func(visitFunc) error) (*svc.ImportBatch, error) {
+	if err := im.checkLimits(ctx, m, base, walk); err != nil {
+		return nil, err
+	}
+
+	// 文件头中的大小可能与实际不符，按实际读取的字节数再次限制总大小
+	var total int64
+	budget := func(read func() ([]byte, error)) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			data, err := read()
+			if err != nil {
+				return nil, err
+			}
+			if im.MaxBytes > 0 && total+int64(len(data)) > im.MaxBytes {
+				return nil, fmt.Errorf("%w：文件总大小超过 %d 字节", ErrBatchTooLarge, im.MaxBytes)
+			}
+			total += int64(len(data))
+			return data, nil
+		}
+	}
+
+	sel := newSelection(m, base)
+	var items []svc.ImportItem
+	err := walk(func(name string, size int64, open func() (io.ReadCloser, error)) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		e := sel.pick(name)
+		if e == nil {
+			return nil
+		}
+		items = append(items, im.enqueue(ctx, m, e, budget(func() ([]byte, error) {
+			if size > im.maxSize {
+				return nil, svc.ErrFileTooLarge
+			}
+			f, err := open()
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return im.read(f)
+		})))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range m.Files {
+		e := &m.Files[i]
+		switch {
+		case e.URL != "":
+			items = append(items, im.enqueue(ctx, m, e, budget(func() ([]byte, error) { return im.download(ctx, e.URL) })))
+		case !sel.found[e]:
+			items = append(items, im.rejected(m, e, errors.New("文件不存在")))
+		}
+	}
+	if len(items) == 0 {
+		return nil, ErrNoFiles
+	}
+	return im.q.SaveBatch(ctx, items)
+}
+
+// enqueue 读取文件并按内容识别格式，支持的文件创建导入任务
+func (im *Importer) enqueue(ctx context.Context, m *Manifest, e *Entry, read func() ([]byte, error)) svc.ImportItem {
+	data, err := read()
+	if err != nil {
+		return im.rejected(m, e, err)
+	}
+	if _, err := extractor.Detect(bytes.NewReader(data), int64(len(data))); err != nil {
+		return im.rejected(m, e, err)
+	}
+	job, err := im.q.Enqueue(ctx, e.Title, e.filename(), im.meta(m, e), data)
+	if err != nil {
+		return im.rejected(m, e, err)
+	}
+	return svc.ImportItem{Path: e.source(), Title: e.Title, Collection: job.Collection, Tags: job.TagList(), JobID: job.ID, Status: job.Status}
+}
+
+func (im *Importer) rejected(m *Manifest, e *Entry, err error) svc.ImportItem {
+	meta := im.meta(m, e)
+	return svc.ImportItem{Path: e.source(), Title: e.Title, Collection: meta.Collection, Tags: svc.ParseTags(strings.Join(meta.Tags, ",")), Status: svc.ItemRejected, Error: err.Error()}
+}
+
+// meta 集合、主题、难度与语言按条目、清单、命令行或请求的顺序取第一个非空值；标签合并三者
+func (im *Importer) meta(m *Manifest, e *Entry) svc.KnowledgeMeta {
+	return svc.KnowledgeMeta{
+		Collection: cmp.Or(e.Collection, m.Collection, im.Defaults.Collection),
+		Topic:      cmp.Or(e.Topic, m.Topic, im.Defaults.Topic),
+		Difficulty: cmp.Or(e.Difficulty, m.Difficulty, im.Defaults.Difficulty),
+		Language:   cmp.Or(e.Language, m.Language, im.Defaults.Language),
+		Tags:       slices.Concat(im.Defaults.Tags, m.Tags, e.Tags),
+		Source:     e.source(),
+	}
+}
+
+// read 读取文件内容，超过大小限制时返回 ErrFileTooLarge
+func (im *Importer) read(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, im.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > im.maxSize {
+		return nil, svc.ErrFileTooLarge
+	}
+	return data, nil
+}
+
+func (im *Importer) download(ctx context.Context, rawURL string) ([]byte, error) {
+	if im.HTTPClient == nil {
+		return nil, ErrURLNotAllowed
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := im.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下载失败: %s", resp.Status)
+	}
+	if resp.ContentLength > im.maxSize {
+		return nil, svc.ErrFileTooLarge
+	}
+	return im.read(resp.Body)
+}
+
+// source 批次中显示的来源：相对路径或URL
+func (e *Entry) source() string {
+	if e.URL != "" {
+		return e.URL
+	}
+	return e.Path
+}
+
+// filename 任务的文件名，用于推断类型与缺省标题
+func (e *Entry) filename() string {
+	if e.URL == "" {
+		return path.Base(cleanPath(e.Path))
+	}
+	if u, err := url.Parse(e.URL); err == nil {
+		if name := path.Base(u.Path); name != "/" && name != "." {
+			return name
+		}
+	}
+	return "download"
+}
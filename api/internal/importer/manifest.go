@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"ai-gozero-agent/api/internal/svc"
+)
+
+// ManifestName 目录或归档中的导入清单文件名
+const ManifestName = "manifest.json"
+
+// Manifest 导入清单，Files 为空时导入目录或归档中的全部文件；
+// 清单级的集合、主题、难度与语言为文件的缺省值，标签与文件的标签合并
+type Manifest struct {
+	svc.KnowledgeMeta
+	Files []Entry `json:"files,omitempty"`
+}
+
+// Entry 清单中的一个文件，Path 与 URL 二选一，元数据为空的字段使用清单或导入时指定的值
+type Entry struct {
+	Path  string `json:"path,omitempty"` // 相对清单所在目录的路径
+	URL   string `json:"url,omitempty"`  // 下载地址，仅命令行导入支持
+	Title string `json:"title,omitempty"`
+	svc.KnowledgeMeta
+}
+
+// LoadManifest 解析导入清单
+func LoadManifest(r io.Reader) (*Manifest, error) {
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("解析导入清单失败: %w", err)
+	}
+	if _, err := m.Normalize(); err != nil {
+		return nil, err
+	}
+	for i, e := range m.Files {
+		if _, err := e.Normalize(); err != nil {
+			return nil, fmt.Errorf("导入清单第%d项: %w", i+1, err)
+		}
+		if (e.Path == "") == (e.URL == "") {
+			return nil, fmt.Errorf("导入清单第%d项需指定 path 或 url 之一", i+1)
+		}
+		if e.URL != "" && !strings.HasPrefix(e.URL, "http://") && !strings.HasPrefix(e.URL, "https://") {
+			return nil, fmt.Errorf("导入清单第%d项的url仅支持http与https: %s", i+1, e.URL)
+		}
+	}
+	return &m, nil
+}
+
+// LoadManifestFile 读取导入清单文件
+func LoadManifestFile(name string) (*Manifest, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadManifest(f)
+}
+
+// entries 按归一化路径索引清单中的文件，base 为清单所在目录
+func (m *Manifest) entries(base string) map[string]*Entry {
+	if len(m.Files) == 0 {
+		return nil
+	}
+	entries := make(map[string]*Entry)
+	for i := range m.Files {
+		if e := &m.Files[i]; e.Path != "" {
+			entries[path.Join(base, cleanPath(e.Path))] = e
+		}
+	}
+	return entries
+}
+
+// cleanPath 统一为不以 / 开头的斜杠路径
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, `\`, "/")), "/")
+}
+
+// hidden 隐藏文件与系统生成的文件（如 .DS_Store、__MACOSX）不导入
+func hidden(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const dashScopeGenerationPath = "/api/v1/services/aigc/text-generation/generation"
+
+// DashScopeProvider DashScope 原生文本生成接口（SSE增量输出）
+type DashScopeProvider struct {
+	name    string
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func NewDashScopeProvider(name, baseURL, apiKey string) *DashScopeProvider {
+	if baseURL == "" {
+		baseURL = "https://dashscope.aliyuncs.com"
+	}
+	return &DashScopeProvider{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{},
+	}
+}
+
+func (p *DashScopeProvider) Name() string {
+	return p.name
+}
+
+type dashScopeMessage struct {
+	Role       string            `json:"role"`
+	Content    string            `json:"content"`
+	ToolCalls  []openai.ToolCall `json:"tool_calls,omitempty"`
+	Name       string            `json:"name,omitempty"`         // 工具结果对应的工具名
+	ToolCallID string            `json:"tool_call_id,omitempty"` // 工具结果对应的调用ID
+}
+
+type dashScopeRequest struct {
+	Model string `json:"model"`
+	Input struct {
+		Messages []dashScopeMessage `json:"messages"`
+	} `json:"input"`
+	Parameters map[string]any `json:"parameters"`
+}
+
+type dashScopeResponse struct {
+	Output struct {
+		Choices []struct {
+			Message      dashScopeMessage `json:"message"`
+			FinishReason string           `json:"finish_reason"`
+		} `json:"choices"`
+	} `json:"output"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+	RequestID string `json:"request_id"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+func (p *DashScopeProvider) CreateChatCompletionStream(ctx context.Context, req Request) (Stream, error) {
+	body := dashScopeRequest{
+		Model:      req.Model,
+		Parameters: dashScopeParameters(req),
+	}
+	for _, m := range req.Messages {
+		body.Input.Messages = append(body.Input.Messages, dashScopeMessage{
+			Role:       m.Role,
+			Content:    m.Content,
+			ToolCalls:  m.ToolCalls,
+			Name:       m.Name,
+			ToolCallID: m.ToolCallID,
+		})
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+dashScopeGenerationPath, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	httpReq.Header.Set("X-DashScope-SSE", "enable")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var errResp dashScopeResponse
+		raw, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(raw, &errResp) != nil || errResp.Message == "" {
+			errResp.Message = string(raw)
+		}
+		return nil, &StatusError{Provider: p.name, StatusCode: resp.StatusCode, Message: errResp.Message}
+	}
+
+	return &dashScopeStream{
+		body:    resp.Body,
+		scanner: bufio.NewScanner(resp.Body),
+		model:   req.Model,
+		created: time.Now().Unix(),
+	}, nil
+}
+
+func dashScopeParameters(req Request) map[string]any {
+	params := map[string]any{
+		"result_format":      "message",
+		"incremental_output": true,
+	}
+	if req.Temperature != nil {
+		params["temperature"] = *req.Temperature
+	}
+	if req.TopP != 0 {
+		params["top_p"] = req.TopP
+	}
+	if req.MaxTokens != 0 {
+		params["max_tokens"] = req.MaxTokens
+	}
+	if req.PresencePenalty != 0 {
+		params["presence_penalty"] = req.PresencePenalty
+	}
+	if req.Seed != nil && *req.Seed >= 0 {
+		params["seed"] = *req.Seed
+	}
+	if len(req.Stop) > 0 {
+		params["stop"] = req.Stop
+	}
+	if len(req.Tools) > 0 {
+		params["tools"] = req.Tools
+	}
+	return params
+}
+
+type dashScopeStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	model   string
+	created int64
+	done    bool
+}
+
+func (s *dashScopeStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	var event string
+	for !s.done && s.scanner.Scan() {
+		line := s.scanner.Text()
+		if v, ok := strings.CutPrefix(line, "event:"); ok {
+			event = strings.TrimSpace(v)
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+
+		var chunk dashScopeResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return openai.ChatCompletionStreamResponse{}, fmt.Errorf("decode dashscope chunk: %w", err)
+		}
+		if event == "error" || chunk.Code != "" {
+			return openai.ChatCompletionStreamResponse{}, fmt.Errorf("dashscope: %s: %s", chunk.Code, chunk.Message)
+		}
+		if len(chunk.Output.Choices) == 0 {
+			continue
+		}
+
+		choice := chunk.Output.Choices[0]
+		resp := openai.ChatCompletionStreamResponse{
+			ID:      chunk.RequestID,
+			Object:  "chat.completion.chunk",
+			Created: s.created,
+			Model:   s.model,
+			Choices: []openai.ChatCompletionStreamChoice{{
+				Delta: openai.ChatCompletionStreamChoiceDelta{
+					Role:      choice.Message.Role,
+					Content:   choice.Message.Content,
+					ToolCalls: choice.Message.ToolCalls,
+				},
+			}},
+		}
+		if choice.FinishReason != "" && choice.FinishReason != "null" {
+			s.done = true
+			resp.Choices[0].FinishReason = openai.FinishReason(choice.FinishReason)
+			resp.Usage = &openai.Usage{
+				PromptTokens:     chunk.Usage.InputTokens,
+				CompletionTokens: chunk.Usage.OutputTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+		return resp, nil
+	}
+	if err := s.scanner.Err(); err != nil {
+		return openai.ChatCompletionStreamResponse{}, err
+	}
+	return openai.ChatCompletionStreamResponse{}, io.EOF
+}
+
+func (s *dashScopeStream) Close() error {
+	return s.body.Close()
+}
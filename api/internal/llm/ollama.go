@@ -0,0 +1,220 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// OllamaProvider ollama 原生 /api/chat 接口
+type OllamaProvider struct {
+	name    string
+	baseURL string
+	client  *http.Client
+}
+
+func NewOllamaProvider(name, baseURL string) *OllamaProvider {
+	return &OllamaProvider{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{},
+	}
+}
+
+func (p *OllamaProvider) Name() string {
+	return p.name
+}
+
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"` // 工具结果对应的工具名
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"` // JSON对象而非字符串
+	} `json:"function"`
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Tools    []openai.Tool   `json:"tools,omitempty"`
+	Stream   bool            `json:"stream"`
+	Options  map[string]any  `json:"options,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error"`
+}
+
+func (p *OllamaProvider) CreateChatCompletionStream(ctx context.Context, req Request) (Stream, error) {
+	body := ollamaChatRequest{
+		Model:   req.Model,
+		Tools:   req.Tools,
+		Stream:  true,
+		Options: ollamaOptions(req),
+	}
+	for _, m := range req.Messages {
+		msg := ollamaMessage{Role: m.Role, Content: m.Content, ToolName: m.Name}
+		for _, call := range m.ToolCalls {
+			var tc ollamaToolCall
+			tc.Function.Name = call.Function.Name
+			tc.Function.Arguments = json.RawMessage(call.Function.Arguments)
+			if !json.Valid(tc.Function.Arguments) {
+				tc.Function.Arguments = json.RawMessage("{}")
+			}
+			msg.ToolCalls = append(msg.ToolCalls, tc)
+		}
+		body.Messages = append(body.Messages, msg)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/api/chat", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var errResp ollamaChatResponse
+		raw, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(raw, &errResp) != nil || errResp.Error == "" {
+			errResp.Error = string(raw)
+		}
+		return nil, &StatusError{Provider: p.name, StatusCode: resp.StatusCode, Message: errResp.Error}
+	}
+
+	return &ollamaStream{
+		body:    resp.Body,
+		scanner: bufio.NewScanner(resp.Body),
+		created: time.Now().Unix(),
+	}, nil
+}
+
+// ollamaOptions 将OpenAI参数映射为ollama options
+func ollamaOptions(req Request) map[string]any {
+	opts := make(map[string]any)
+	if req.Temperature != nil {
+		opts["temperature"] = *req.Temperature
+	}
+	if req.TopP != 0 {
+		opts["top_p"] = req.TopP
+	}
+	if req.MaxTokens != 0 {
+		opts["num_predict"] = req.MaxTokens
+	}
+	if req.FrequencyPenalty != 0 {
+		opts["frequency_penalty"] = req.FrequencyPenalty
+	}
+	if req.PresencePenalty != 0 {
+		opts["presence_penalty"] = req.PresencePenalty
+	}
+	if req.Seed != nil && *req.Seed >= 0 {
+		opts["seed"] = *req.Seed
+	}
+	if len(req.Stop) > 0 {
+		opts["stop"] = req.Stop
+	}
+	return opts
+}
+
+type ollamaStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	created int64
+	done    bool
+
+	toolCalls int // 已返回的工具调用数量
+}
+
+func (s *ollamaStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	for {
+		if s.done || !s.scanner.Scan() {
+			if err := s.scanner.Err(); err != nil {
+				return openai.ChatCompletionStreamResponse{}, err
+			}
+			return openai.ChatCompletionStreamResponse{}, io.EOF
+		}
+		line := bytes.TrimSpace(s.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return openai.ChatCompletionStreamResponse{}, fmt.Errorf("decode ollama chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return openai.ChatCompletionStreamResponse{}, fmt.Errorf("ollama: %s", chunk.Error)
+		}
+
+		resp := openai.ChatCompletionStreamResponse{
+			Object:  "chat.completion.chunk",
+			Created: s.created,
+			Model:   chunk.Model,
+			Choices: []openai.ChatCompletionStreamChoice{{
+				Delta: openai.ChatCompletionStreamChoiceDelta{
+					Role:    chunk.Message.Role,
+					Content: chunk.Message.Content,
+				},
+			}},
+		}
+		// ollama 一次返回完整的工具调用，转换为带 index 的增量
+		for _, tc := range chunk.Message.ToolCalls {
+			index := s.toolCalls
+			s.toolCalls++
+			resp.Choices[0].Delta.ToolCalls = append(resp.Choices[0].Delta.ToolCalls, openai.ToolCall{
+				Index: &index,
+				ID:    fmt.Sprintf("call_%d", index),
+				Type:  openai.ToolTypeFunction,
+				Function: openai.FunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: string(tc.Function.Arguments),
+				},
+			})
+		}
+		if chunk.Done {
+			s.done = true
+			resp.Choices[0].FinishReason = openai.FinishReason(chunk.DoneReason)
+			if s.toolCalls > 0 {
+				resp.Choices[0].FinishReason = openai.FinishReasonToolCalls
+			}
+			resp.Usage = &openai.Usage{
+				PromptTokens:     chunk.PromptEvalCount,
+				CompletionTokens: chunk.EvalCount,
+				TotalTokens:      chunk.PromptEvalCount + chunk.EvalCount,
+			}
+		}
+		return resp, nil
+	}
+}
+
+func (s *ollamaStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,41 @@
+package llm
+
+import (
+	"context"
+	"math"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// OpenAIProvider OpenAI兼容接口
+type OpenAIProvider struct {
+	name         string
+	client       *openai.Client
+	includeUsage bool // 是否请求 stream_options.include_usage
+}
+
+func NewOpenAIProvider(name, baseURL, apiKey string, includeUsage bool) *OpenAIProvider {
+	conf := openai.DefaultConfig(apiKey)
+	conf.BaseURL = baseURL
+	return &OpenAIProvider{
+		name:         name,
+		client:       openai.NewClientWithConfig(conf),
+		includeUsage: includeUsage,
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return p.name
+}
+
+func (p *OpenAIProvider) CreateChatCompletionStream(ctx context.Context, req Request) (Stream, error) {
+	if p.includeUsage && req.StreamOptions == nil {
+		req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	}
+	creq := req.ChatCompletionRequest
+	if req.Temperature != nil {
+		// go-openai 省略为0的温度，以最小正数表示0
+		creq.Temperature = max(*req.Temperature, math.SmallestNonzeroFloat32)
+	}
+	return p.client.CreateChatCompletionStream(ctx, creq)
+}
@@ -0,0 +1,78 @@
+// Package llm 大模型提供方抽象：统一使用 go-openai 的请求/响应结构，
+// 由各提供方实现负责协议转换，Router 负责按顺序故障转移
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// 提供方类型
+const (
+	TypeOpenAI    = "openai"    // OpenAI兼容接口（含 ollama /v1、DashScope compatible-mode 等）
+	TypeOllama    = "ollama"    // ollama 原生 /api/chat
+	TypeDashScope = "dashscope" // DashScope 原生文本生成接口
+)
+
+// Provider 大模型提供方
+type Provider interface {
+	Name() string
+	CreateChatCompletionStream(ctx context.Context, req Request) (Stream, error)
+}
+
+// Request 对话请求。openai.ChatCompletionRequest 会省略为0的温度，
+// Temperature 覆盖其同名字段：nil 时使用提供方默认温度，指向0时按0传给提供方
+type Request struct {
+	openai.ChatCompletionRequest
+	Temperature *float32
+}
+
+// Stream 流式响应，Recv 在结束时返回 io.EOF
+type Stream interface {
+	Recv() (openai.ChatCompletionStreamResponse, error)
+	Close() error
+}
+
+// StatusError 提供方返回的非200响应
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// Retryable 判断错误是否应切换到下一个提供方：连接错误或5xx；调用方取消或超时不切换
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	// RequestError 可能包裹未设置状态码的 APIError，需先判断
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatusCode >= 500
+	}
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,43 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"deadline exceeded", context.DeadlineExceeded, false},
+		{"wrapped deadline exceeded", fmt.Errorf("stream: %w", context.DeadlineExceeded), false},
+		{"url error with deadline exceeded", &url.Error{Op: "Post", URL: "http://llm", Err: context.DeadlineExceeded}, false},
+		{"status 500", &StatusError{Provider: "p", StatusCode: 500}, true},
+		{"status 429", &StatusError{Provider: "p", StatusCode: 429}, false},
+		{"wrapped status 503", fmt.Errorf("call: %w", &StatusError{Provider: "p", StatusCode: 503}), true},
+		{"request error 502", &openai.RequestError{HTTPStatusCode: 502, Err: errors.New("bad gateway")}, true},
+		{"request error 400", &openai.RequestError{HTTPStatusCode: 400, Err: &openai.APIError{}}, false},
+		{"api error 503", &openai.APIError{HTTPStatusCode: 503}, true},
+		{"api error 401", &openai.APIError{HTTPStatusCode: 401}, false},
+		{"net error", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"url error", &url.Error{Op: "Post", URL: "http://llm", Err: errors.New("EOF")}, true},
+		{"other", errors.New("invalid request"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Retryable(tt.err); got != tt.want {
+				t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
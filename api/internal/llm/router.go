@@ -0,0 +1,193 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"ai-gozero-agent/api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ErrNoProvider 未配置任何提供方
+var ErrNoProvider = errors.New("llm: no provider configured")
+
+// Router 按配置顺序调用提供方，连接错误或5xx时自动切换到下一个，并记录各提供方健康状态
+type Router struct {
+	providers        []*routedProvider
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+type routedProvider struct {
+	Provider
+	typ    string
+	model  string            // 默认模型
+	models map[string]string // 模型别名 -> 该提供方的实际模型名
+
+	lock           sync.Mutex
+	failures       int       // 连续失败次数
+	unhealthyUntil time.Time // 熔断截止时间
+	lastError      string
+	lastFailure    time.Time
+}
+
+// ProviderHealth 提供方健康状态
+type ProviderHealth struct {
+	Name           string
+	Type           string
+	Healthy        bool
+	Failures       int
+	LastError      string
+	LastFailure    time.Time
+	UnhealthyUntil time.Time
+}
+
+func NewRouter(c config.LLMConfig) (*Router, error) {
+	r := &Router{
+		failureThreshold: c.FailureThreshold,
+		cooldown:         c.Cooldown,
+	}
+	for _, pc := range c.Providers {
+		p, err := NewProvider(pc)
+		if err != nil {
+			return nil, err
+		}
+		r.providers = append(r.providers, &routedProvider{
+			Provider: p,
+			typ:      pc.Type,
+			model:    pc.Model,
+			models:   pc.Models,
+		})
+	}
+	return r, nil
+}
+
+// NewProvider 根据配置创建提供方
+func NewProvider(c config.LLMProvider) (Provider, error) {
+	switch c.Type {
+	case TypeOpenAI, "":
+		return NewOpenAIProvider(c.Name, c.BaseURL, c.ApiKey, c.IncludeUsage), nil
+	case TypeOllama:
+		return NewOllamaProvider(c.Name, c.BaseURL), nil
+	case TypeDashScope:
+		return NewDashScopeProvider(c.Name, c.BaseURL, c.ApiKey), nil
+	default:
+		return nil, fmt.Errorf("llm: unknown provider type %q", c.Type)
+	}
+}
+
+func (r *Router) Name() string {
+	return "router"
+}
+
+// CreateChatCompletionStream 依次尝试健康的提供方，全部熔断时仍按顺序尝试熔断中的提供方
+func (r *Router) CreateChatCompletionStream(ctx context.Context, req Request) (Stream, error) {
+	if len(r.providers) == 0 {
+		return nil, ErrNoProvider
+	}
+
+	var healthy, unhealthy []*routedProvider
+	now := time.Now()
+	for _, p := range r.providers {
+		if p.healthy(now) {
+			healthy = append(healthy, p)
+		} else {
+			unhealthy = append(unhealthy, p)
+		}
+	}
+
+	var errs []error
+	for _, p := range append(healthy, unhealthy...) {
+		preq := req
+		preq.Model = p.resolveModel(req.Model)
+
+		stream, err := p.CreateChatCompletionStream(ctx, preq)
+		if err == nil {
+			p.markSuccess()
+			return &routedStream{Stream: stream, provider: p.Name(), model: preq.Model}, nil
+		}
+		// 请求已取消或超时时不是提供方的问题，不记录失败也不再切换
+		if ctx.Err() != nil || !Retryable(err) {
+			return nil, err
+		}
+
+		logx.WithContext(ctx).Errorf("llm provider %s failed, try next: %v", p.Name(), err)
+		p.markFailure(err, r.failureThreshold, r.cooldown)
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("llm: all providers failed: %w", errors.Join(errs...))
+}
+
+// Health 返回各提供方健康状态
+func (r *Router) Health() []ProviderHealth {
+	now := time.Now()
+	health := make([]ProviderHealth, 0, len(r.providers))
+	for _, p := range r.providers {
+		p.lock.Lock()
+		health = append(health, ProviderHealth{
+			Name:           p.Name(),
+			Type:           p.typ,
+			Healthy:        now.After(p.unhealthyUntil),
+			Failures:       p.failures,
+			LastError:      p.lastError,
+			LastFailure:    p.lastFailure,
+			UnhealthyUntil: p.unhealthyUntil,
+		})
+		p.lock.Unlock()
+	}
+	return health
+}
+
+// routedStream 记录实际提供服务的提供方与模型
+type routedStream struct {
+	Stream
+	provider string
+	model    string
+}
+
+// StreamSource 返回流实际使用的提供方与模型，非Router创建的流返回空
+func StreamSource(s Stream) (provider, model string) {
+	if rs, ok := s.(*routedStream); ok {
+		return rs.provider, rs.model
+	}
+	return "", ""
+}
+
+// resolveModel 请求模型命中别名时使用别名映射，未指定模型时使用提供方默认模型，其余模型名原样传递
+func (p *routedProvider) resolveModel(model string) string {
+	if m, ok := p.models[model]; ok {
+		return m
+	}
+	if model == "" {
+		return p.model
+	}
+	return model
+}
+
+func (p *routedProvider) healthy(now time.Time) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return now.After(p.unhealthyUntil)
+}
+
+func (p *routedProvider) markSuccess() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.failures = 0
+	p.unhealthyUntil = time.Time{}
+}
+
+func (p *routedProvider) markFailure(err error, threshold int, cooldown time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.failures++
+	p.lastError = err.Error()
+	p.lastFailure = time.Now()
+	if p.failures >= threshold {
+		p.unhealthyUntil = p.lastFailure.Add(cooldown)
+	}
+}
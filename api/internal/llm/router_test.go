@@ -0,0 +1,189 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// fakeProvider 按顺序返回预设错误，错误用尽后成功
+type fakeProvider struct {
+	name   string
+	errs   []error
+	calls  int
+	models []string
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) CreateChatCompletionStream(ctx context.Context, req Request) (Stream, error) {
+	p.calls++
+	p.models = append(p.models, req.Model)
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		return nil, err
+	}
+	return fakeStream{}, nil
+}
+
+type fakeStream struct{}
+
+func (fakeStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	return openai.ChatCompletionStreamResponse{}, io.EOF
+}
+
+func (fakeStream) Close() error { return nil }
+
+func newTestRouter(providers ...*fakeProvider) *Router {
+	r := &Router{failureThreshold: 1, cooldown: time.Minute}
+	for _, p := range providers {
+		r.providers = append(r.providers, &routedProvider{Provider: p, model: p.name + "-default"})
+	}
+	return r
+}
+
+var (
+	errUnavailable = &StatusError{Provider: "fake", StatusCode: 503, Message: "unavailable"}
+	errBadRequest  = &StatusError{Provider: "fake", StatusCode: 400, Message: "bad request"}
+)
+
+func TestRouterFailover(t *testing.T) {
+	tests := []struct {
+		name      string
+		primary   []error
+		secondary []error
+		wantErr   bool
+		wantFrom  string // 提供服务的提供方
+		wantCalls [2]int
+		wantDown  [2]bool // 调用后是否熔断
+	}{
+		{
+			name:      "primary healthy",
+			wantFrom:  "primary",
+			wantCalls: [2]int{1, 0},
+		},
+		{
+			name:      "retryable error fails over",
+			primary:   []error{errUnavailable},
+			wantFrom:  "secondary",
+			wantCalls: [2]int{1, 1},
+			wantDown:  [2]bool{true, false},
+		},
+		{
+			name:      "non-retryable error returns",
+			primary:   []error{errBadRequest},
+			wantErr:   true,
+			wantCalls: [2]int{1, 0},
+		},
+		{
+			name:      "deadline exceeded does not fail over",
+			primary:   []error{context.DeadlineExceeded},
+			wantErr:   true,
+			wantCalls: [2]int{1, 0},
+		},
+		{
+			name:      "all providers fail",
+			primary:   []error{errUnavailable},
+			secondary: []error{errUnavailable},
+			wantErr:   true,
+			wantCalls: [2]int{1, 1},
+			wantDown:  [2]bool{true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeProvider{name: "primary", errs: tt.primary}
+			secondary := &fakeProvider{name: "secondary", errs: tt.secondary}
+			r := newTestRouter(primary, secondary)
+
+			stream, err := r.CreateChatCompletionStream(context.Background(), Request{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if from, _ := StreamSource(stream); from != tt.wantFrom {
+					t.Errorf("served by %q, want %q", from, tt.wantFrom)
+				}
+			}
+			if calls := [2]int{primary.calls, secondary.calls}; calls != tt.wantCalls {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+			health := r.Health()
+			if down := [2]bool{!health[0].Healthy, !health[1].Healthy}; down != tt.wantDown {
+				t.Errorf("unhealthy = %v, want %v", down, tt.wantDown)
+			}
+		})
+	}
+}
+
+func TestRouterSkipsUnhealthy(t *testing.T) {
+	primary := &fakeProvider{name: "primary", errs: []error{errUnavailable}}
+	secondary := &fakeProvider{name: "secondary"}
+	r := newTestRouter(primary, secondary)
+
+	for range 2 {
+		if _, err := r.CreateChatCompletionStream(context.Background(), Request{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 第二次请求不再调用熔断中的 primary
+	if primary.calls != 1 || secondary.calls != 2 {
+		t.Errorf("calls = %d/%d, want 1/2", primary.calls, secondary.calls)
+	}
+
+	// 全部熔断时仍按配置顺序尝试
+	primary.errs = []error{errUnavailable}
+	secondary.errs = []error{errUnavailable}
+	if _, err := r.CreateChatCompletionStream(context.Background(), Request{}); err == nil {
+		t.Fatal("want error when all providers fail")
+	}
+	stream, err := r.CreateChatCompletionStream(context.Background(), Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, _ := StreamSource(stream); from != "primary" {
+		t.Errorf("served by %q, want primary", from)
+	}
+}
+
+func TestRouterContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	primary := &fakeProvider{name: "primary", errs: []error{errUnavailable}}
+	secondary := &fakeProvider{name: "secondary"}
+	r := newTestRouter(primary, secondary)
+
+	// 请求取消后提供方返回的错误不计入失败，也不再切换
+	cancel()
+	_, err := r.CreateChatCompletionStream(ctx, Request{})
+	if !errors.Is(err, errUnavailable) {
+		t.Fatalf("err = %v, want %v", err, errUnavailable)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("secondary called %d times after cancel", secondary.calls)
+	}
+	if h := r.Health()[0]; !h.Healthy || h.Failures != 0 {
+		t.Errorf("primary health = %+v, want healthy without failures", h)
+	}
+}
+
+func TestRouterResolveModel(t *testing.T) {
+	p := &fakeProvider{name: "primary"}
+	r := newTestRouter(p)
+	r.providers[0].models = map[string]string{"fast": "qwen2.5:3b"}
+
+	for _, model := range []string{"fast", "", "gpt-4o"} {
+		if _, err := r.CreateChatCompletionStream(context.Background(), Request{ChatCompletionRequest: openai.ChatCompletionRequest{Model: model}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"qwen2.5:3b", "primary-default", "gpt-4o"}
+	if !slices.Equal(p.models, want) {
+		t.Errorf("models = %v, want %v", p.models, want)
+	}
+}
@@ -0,0 +1,76 @@
+package llm
+
+import "strings"
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// ThinkFilter 流式分离推理模型（deepseek-r1 等）输出的 <think>…</think> 推理内容与正式回答，
+// 标签可能被拆分在多个增量中，无法确定的尾部暂存到下一次输入
+type ThinkFilter struct {
+	inThink bool
+	pending string
+}
+
+// Feed 输入一段增量，返回可确定的正式回答和推理内容
+func (f *ThinkFilter) Feed(delta string) (answer, reasoning string) {
+	var answerBuf, reasoningBuf strings.Builder
+	s := f.pending + delta
+	f.pending = ""
+
+	for s != "" {
+		tag := thinkOpenTag
+		if f.inThink {
+			tag = thinkCloseTag
+		}
+
+		out := &answerBuf
+		if f.inThink {
+			out = &reasoningBuf
+		}
+
+		if i := strings.Index(s, tag); i >= 0 {
+			out.WriteString(s[:i])
+			s = s[i+len(tag):]
+			f.inThink = !f.inThink
+			continue
+		}
+
+		// 末尾可能是被截断的标签，暂存
+		keep := partialSuffix(s, tag)
+		out.WriteString(s[:len(s)-keep])
+		f.pending = s[len(s)-keep:]
+		break
+	}
+	return answerBuf.String(), reasoningBuf.String()
+}
+
+// Flush 流结束时输出暂存内容
+func (f *ThinkFilter) Flush() (answer, reasoning string) {
+	s := f.pending
+	f.pending = ""
+	if f.inThink {
+		return "", s
+	}
+	return s, ""
+}
+
+// StripThink 去除完整文本中的推理内容
+func StripThink(s string) string {
+	var f ThinkFilter
+	answer, _ := f.Feed(s)
+	rest, _ := f.Flush()
+	return strings.TrimSpace(answer + rest)
+}
+
+// partialSuffix 返回s末尾与tag前缀重合的最大长度
+func partialSuffix(s, tag string) int {
+	for n := min(len(s), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package llm
+
+import "testing"
+
+func TestThinkFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunks    []string
+		answer    string
+		reasoning string
+	}{
+		{
+			name:   "no think",
+			chunks: []string{"hello ", "world"},
+			answer: "hello world",
+		},
+		{
+			name:      "whole tags",
+			chunks:    []string{"<think>plan</think>answer"},
+			answer:    "answer",
+			reasoning: "plan",
+		},
+		{
+			name:      "open tag split",
+			chunks:    []string{"<thi", "nk>plan</think>answer"},
+			answer:    "answer",
+			reasoning: "plan",
+		},
+		{
+			name:      "close tag split",
+			chunks:    []string{"<think>pl", "an</th", "ink>ans", "wer"},
+			answer:    "answer",
+			reasoning: "plan",
+		},
+		{
+			name:      "tags split into single bytes",
+			chunks:    []string{"<", "t", "h", "i", "n", "k", ">", "p", "<", "/", "t", "h", "i", "n", "k", ">", "a"},
+			answer:    "a",
+			reasoning: "p",
+		},
+		{
+			name:   "partial tag that is not a tag",
+			chunks: []string{"a <th", "en b"},
+			answer: "a <then b",
+		},
+		{
+			name:   "partial tag at end of stream",
+			chunks: []string{"a <thi"},
+			answer: "a <thi",
+		},
+		{
+			name:      "unterminated think",
+			chunks:    []string{"<think>plan </thi"},
+			reasoning: "plan </thi",
+		},
+		{
+			name:      "multibyte content",
+			chunks:    []string{"<think>思", "考</think", ">回答"},
+			answer:    "回答",
+			reasoning: "思考",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f ThinkFilter
+			var answer, reasoning string
+			for _, chunk := range tt.chunks {
+				a, r := f.Feed(chunk)
+				answer += a
+				reasoning += r
+			}
+			a, r := f.Flush()
+			answer += a
+			reasoning += r
+			if answer != tt.answer || reasoning != tt.reasoning {
+				t.Errorf("answer = %q, reasoning = %q, want %q, %q", answer, reasoning, tt.answer, tt.reasoning)
+			}
+		})
+	}
+}
+
+func TestStripThink(t *testing.T) {
+	if got := StripThink("<think>plan</think>\n\nanswer "); got != "answer" {
+		t.Errorf("StripThink = %q, want %q", got, "answer")
+	}
+}
@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"unicode"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// 每条消息的格式开销（角色、分隔符）
+const messageOverheadTokens = 4
+
+// EstimateTokens 粗略估算token数：中日韩字符约1个token，其余字符约4个字符1个token，
+// 仅在提供方未返回用量时使用
+func EstimateTokens(text string) int {
+	var cjk, other int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hiragana, r),
+			unicode.Is(unicode.Katakana, r), unicode.Is(unicode.Hangul, r):
+			cjk++
+		default:
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// EstimateMessagesTokens 估算请求消息的token数
+func EstimateMessagesTokens(messages []openai.ChatCompletionMessage) int {
+	total := 0
+	for _, m := range messages {
+		total += messageOverheadTokens + EstimateTokens(m.Content)
+	}
+	return total
+}
@@ -0,0 +1,37 @@
+package logic
+
+import (
+	"context"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ChatCancelLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 取消进行中的生成
+func NewChatCancelLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ChatCancelLogic {
+	return &ChatCancelLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ChatCancelLogic) ChatCancel(req *types.ChatCancelReq) (resp *types.ChatCancelResp, err error) {
+	cancelled, err := l.svcCtx.Generations.Cancel(req.ChatId, req.KeepPartial)
+	if err != nil {
+		return nil, err
+	}
+	l.Logger.Infof("cancel generation, chatId: %s, cancelled: %v", req.ChatId, cancelled)
+
+	return &types.ChatCancelResp{
+		Cancelled: cancelled,
+	}, nil
+}
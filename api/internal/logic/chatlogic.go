@@ -1,7 +1,10 @@
 package logic
 
 import (
+	"ai-gozero-agent/api/internal/llm"
+	"ai-gozero-agent/api/internal/tools"
 	"ai-gozero-agent/api/internal/utils"
+	"ai-gozero-agent/api/sse"
 	"context"
 	"errors"
 	"fmt"
@@ -14,10 +17,20 @@ import (
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
+var (
+	ErrGenerationBusy      = svc.ErrGenerationBusy
+	ErrNothingToRegenerate = errors.New("没有可重新生成的回答")
+)
+
 type ChatLogic struct {
 	logx.Logger
 	ctx    context.Context
 	svcCtx *svc.ServiceContext
+
+	reasoning bool // 是否推送推理内容
+
+	attachmentName    string // 本轮附件文件名
+	attachmentContent string // 本轮附件文本，超过内联长度时存入会话级知识库
 }
 
 // SSE流式接口
@@ -29,112 +42,566 @@ func NewChatLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ChatLogic {
 	}
 }
 
-func (l *ChatLogic) Chat(req *types.InterViewAPPChatReq) (<-chan *types.ChatResponse, error) {
-	ch := make(chan *types.ChatResponse)
+// WithReasoning 设置订阅者是否接收推理内容（仅面试官）
+func (l *ChatLogic) WithReasoning(show bool) *ChatLogic {
+	l.reasoning = show
+	return l
+}
+
+// WithAttachment 设置本轮附件，取得生成名额后再存入会话级知识库，被拒绝的请求不保存
+func (l *ChatLogic) WithAttachment(filename, content string) *ChatLogic {
+	l.attachmentName = filename
+	l.attachmentContent = content
+	return l
+}
+
+// Chat 开启一轮对话；携带 collections 时保存为会话检索的知识库集合
+func (l *ChatLogic) Chat(req *types.InterViewAPPChatReq) (<-chan *sse.Event, error) {
+	if req.Collections != "" {
+		collections, err := svc.ParseCollections(req.Collections)
+		if err != nil {
+			return nil, err
+		}
+		if err := NewStateManager(l.svcCtx).SetCollections(req.ChatId, collections); err != nil {
+			return nil, err
+		}
+	}
+	return l.start(req, nil)
+}
+
+// Regenerate 重新生成最近一轮回答：删除已保存的回答，回滚该轮的状态变更后重新生成并评估
+func (l *ChatLogic) Regenerate(chatId string) (<-chan *sse.Event, error) {
+	return l.start(&types.InterViewAPPChatReq{ChatId: chatId}, func(req *types.InterViewAPPChatReq) error {
+		userMessage, err := l.svcCtx.VectorStore.LastUserMessage(chatId)
+		if err != nil {
+			return err
+		}
+		if userMessage == "" {
+			return ErrNothingToRegenerate
+		}
+		req.Message = userMessage
+
+		// 上一轮未保存回答（如生成失败）时没有可回滚的状态变更
+		deleted, err := l.svcCtx.VectorStore.DeleteLastAssistantMessage(chatId)
+		if err != nil {
+			return err
+		}
+		if deleted == 1 {
+			if _, err := NewStateManager(l.svcCtx).RollbackState(chatId); err != nil {
+				l.Logger.Errorf("rollback state failed: %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// start 登记并开启一轮生成，订阅其事件；会话已有进行中的生成时返回 ErrGenerationBusy。
+// regenerate 不为nil时为重新生成：登记后执行，不重复保存用户消息
+func (l *ChatLogic) start(req *types.InterViewAPPChatReq, regenerate func(req *types.InterViewAPPChatReq) error) (<-chan *sse.Event, error) {
+	// 生成与客户端连接解耦：客户端断开后仍完成生成并保存回复，事件缓存在Redis中供续传
+	genCtx, cancel := context.WithTimeout(context.WithoutCancel(l.ctx), l.svcCtx.Config.Stream.GenerationTimeout)
+	gen, err := l.svcCtx.Generations.TryRegister(req.ChatId, cancel)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	started := false
+	defer func() {
+		if !started {
+			l.svcCtx.Generations.Unregister(req.ChatId, gen)
+			cancel()
+		}
+	}()
+
+	if regenerate != nil {
+		if err := regenerate(req); err != nil {
+			return nil, err
+		}
+	} else {
+		l.saveAttachment(req.ChatId)
+	}
+	turn, err := l.svcCtx.EventBuffer.StartTurn(req.ChatId)
+	if err != nil {
+		return nil, err
+	}
+
+	started = true
+	go func() {
+		defer cancel()
+		defer l.svcCtx.Generations.Unregister(req.ChatId, gen)
+		l.generate(genCtx, req, turn, gen, regenerate != nil)
+	}()
+
+	return l.subscribe(req.ChatId, turn, "0"), nil
+}
+
+// saveAttachment 超过内联长度的附件分块存入会话级知识库，后续每轮对话按需检索；短附件已拼接进消息，不重复存入
+func (l *ChatLogic) saveAttachment(chatId string) {
+	knowledgeCfg := l.svcCtx.Config.VectorDB.Knowledge
+	if len([]rune(l.attachmentContent)) <= knowledgeCfg.AttachmentInlineLimit {
+		return
+	}
+	n, err := l.svcCtx.VectorStore.SaveSessionKnowledge(chatId, l.attachmentName, l.attachmentContent, knowledgeCfg.MaxChunkSize)
+	if err != nil {
+		l.Logger.Errorf("save session knowledge failed, err:%v", err)
+		return
+	}
+	l.Logger.Infof("saved %d session knowledge chunks for chat %s", n, chatId)
+}
+
+// Resume 断线重连：从Last-Event-ID之后继续推送事件，未携带ID时从最近一轮开头推送
+func (l *ChatLogic) Resume(chatId, lastEventID string) (<-chan *sse.Event, error) {
+	if lastEventID == "" {
+		turn, err := l.svcCtx.EventBuffer.CurrentTurn(chatId)
+		if err != nil {
+			return nil, err
+		}
+		return l.subscribe(chatId, turn, "0"), nil
+	}
+
+	turn, afterID, err := svc.ParseEventID(lastEventID)
+	if err != nil {
+		return nil, err
+	}
+	return l.subscribe(chatId, turn, afterID), nil
+}
+
+// subscribe 从事件缓存读取本轮事件转发给客户端，读到结束事件或客户端断开时退出
+func (l *ChatLogic) subscribe(chatId string, turn int64, afterID string) <-chan *sse.Event {
+	ch := make(chan *sse.Event)
 
 	go func() {
 		defer close(ch)
 
-		// 1.保存用户消息到向量数据库
+		for {
+			events, err := l.svcCtx.EventBuffer.Read(l.ctx, chatId, turn, afterID)
+			if l.ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, svc.ErrStreamExpired) {
+				l.send(ch, sse.Error(sse.ErrCodeExpired, err.Error()))
+				l.send(ch, sse.Done(sse.DoneReasonError))
+				return
+			}
+			if err != nil {
+				l.Logger.Errorf("read event buffer failed: %v", err)
+				l.send(ch, sse.Error(sse.ErrCodeInternal, "read event stream failed"))
+				l.send(ch, sse.Done(sse.DoneReasonError))
+				return
+			}
+
+			for _, e := range events {
+				_, afterID, _ = svc.ParseEventID(e.ID)
+				if e.Type == sse.EventReasoning && !l.reasoning {
+					continue
+				}
+				if !l.send(ch, e) || e.Type == sse.EventDone {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// generate 执行一轮生成，事件写入事件缓存
+func (l *ChatLogic) generate(ctx context.Context, req *types.InterViewAPPChatReq, turn int64, gen *svc.Generation, regenerate bool) {
+	emit := func(e *sse.Event) {
+		if err := l.svcCtx.EventBuffer.Append(req.ChatId, turn, e); err != nil {
+			l.Logger.Errorf("append event failed: %v", err)
+		}
+	}
+
+	// 1.保存用户消息到向量数据库（重新生成时用户消息已存在）
+	if !regenerate {
 		if err := l.svcCtx.VectorStore.SaveMessage(req.ChatId, openai.ChatMessageRoleUser, req.Message); err != nil {
 			l.Logger.Errorf("save message failed: %v", err)
 			// 不返回，继续处理会话
 		}
-		stateManager := NewStateManager(l.svcCtx)
-		// 获取当前状态
-		currentState, err := stateManager.GetOrInitState(req.ChatId)
-		if err != nil {
-			l.Logger.Errorf("get current state failed: %v", err)
-			currentState = types.StateStart
+	}
+	stateManager := NewStateManager(l.svcCtx)
+	// 获取当前状态
+	currentState, err := stateManager.GetOrInitState(req.ChatId)
+	if err != nil {
+		l.Logger.Errorf("get current state failed: %v", err)
+		currentState = types.StateStart
+	}
+
+	// token预算：接近预算时进入评估总结，用尽后直接结束面试
+	currentState, exhausted := l.checkBudget(emit, req.ChatId, currentState)
+	if exhausted {
+		return
+	}
+
+	// 知识检索（RAG核心），按会话或当前状态选择集合与过滤条件
+	filter := stateManager.KnowledgeFilter(req.ChatId, currentState)
+	knowledge, err := l.svcCtx.VectorStore.RetrieveKnowledge(req.Message, 3, filter)
+	if err != nil {
+		l.Logger.Errorf("retrieve knowledge failed: %v", err)
+		knowledge = []types.KnowledgeChunk{}
+	}
+
+	// 检索本次会话的附件知识（简历、设计文档等）
+	sessionKnowledge, err := l.svcCtx.VectorStore.RetrieveSessionKnowledge(
+		req.ChatId, req.Message, l.svcCtx.Config.VectorDB.Knowledge.SessionTopK)
+	if err != nil {
+		l.Logger.Errorf("retrieve session knowledge failed: %v", err)
+	}
+	knowledge = append(knowledge, sessionKnowledge...)
+	if len(knowledge) > 0 {
+		emit(sse.Sources(toSources(knowledge)))
+	}
+
+	// 2.获取会话历史，构建带状态系统消息
+	message, err := l.buildMessageWithState(req.ChatId, currentState, knowledge)
+	if err != nil {
+		l.Logger.Errorf("get session history failed: %v", err)
+		emit(sse.Error(sse.ErrCodeHistory, "get session history failed"))
+		emit(sse.Done(sse.DoneReasonError))
+		return
+	}
+
+	// 3.创建OpenAI请求，按当前状态选择模型参数
+	request := l.svcCtx.ChatRequest(currentState, message)
+
+	// 工具调用：模型可在作答前检索知识、抽题、评分或显式切换状态
+	var registry *tools.Registry
+	stateSetByTool := false
+	session := &tools.Session{ChatId: req.ChatId, State: currentState, Filter: filter, Emit: emit}
+	session.SetState = func(state string) error {
+		if !stateSetByTool {
+			if err := stateManager.SavePrevState(req.ChatId, currentState); err != nil {
+				return err
+			}
 		}
+		if err := stateManager.SetState(req.ChatId, state); err != nil {
+			return err
+		}
+		stateSetByTool = true
+		if state != session.State {
+			emit(sse.StateChanged(session.State, state))
+			session.State = state
+		}
+		return nil
+	}
+	if cfg := l.svcCtx.Config.Tools; cfg.Enabled {
+		registry = tools.NewDefaultRegistry(l.svcCtx, tools.NewSandboxRunner(l.svcCtx.Sandbox), cfg.Allow)
+		tools.RegisterMCP(registry, l.svcCtx.MCPTools.Tools())
+	}
 
-		// 知识检索（RAG核心）
-		knowledge, err := l.svcCtx.VectorStore.RetrieveKnowledge(req.Message, 3)
-		if err != nil {
-			l.Logger.Errorf("retrieve knowledge failed: %v", err)
-			knowledge = []types.KnowledgeChunk{}
+	// 用量：优先使用提供方返回的用量，未返回时估算，多轮工具调用累加
+	usage := types.TokenUsage{ChatId: req.ChatId, Turn: turn, State: currentState}
+	finishUsage := func() {
+		emit(sse.Usage(sse.UsageData{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			TotalTokens:      usage.TotalTokens,
+			Estimated:        usage.Estimated,
+		}))
+		if err := l.svcCtx.Usage.Record(usage); err != nil {
+			l.Logger.Errorf("record token usage failed: %v", err)
 		}
+	}
 
-		// 2.获取会话历史，构建带状态系统消息
-		message, err := l.buildMessageWithState(req.ChatId, currentState, knowledge)
-		if err != nil {
-			l.Logger.Errorf("get session history failed: %v", err)
-			ch <- &types.ChatResponse{
-				Content: "get session history failed",
-				IsLast:  true,
-			}
+	// 分离推理内容，只有正式回答推送给候选人并计入历史与状态评估
+	var fullResponse strings.Builder
+	var think llm.ThinkFilter
+	emitAnswer := func(answer, reasoning string) {
+		if reasoning != "" && l.svcCtx.Config.Reasoning.Emit {
+			emit(sse.Reasoning(reasoning))
+		}
+		if fullResponse.Len() == 0 {
+			answer = strings.TrimLeft(answer, " \r\n\t") // 推理结束后的空行
 		}
+		if answer != "" {
+			fullResponse.WriteString(answer) // 收集完整响应
+			emit(sse.Token(answer))
+		}
+	}
 
-		// 3.创建OpenAI请求
-		request := openai.ChatCompletionRequest{
-			Model:            l.svcCtx.Config.OpenAI.Model,
-			Messages:         message, // 使用会话历史
-			Stream:           true,
-			MaxTokens:        l.svcCtx.Config.OpenAI.MaxTokens,
-			Temperature:      l.svcCtx.Config.OpenAI.Temperature,
-			TopP:             l.svcCtx.Config.OpenAI.TopP,
-			FrequencyPenalty: l.svcCtx.Config.OpenAI.FrequencyPenalty,
-			PresencePenalty:  l.svcCtx.Config.OpenAI.PresencePenalty,
-			Seed:             l.svcCtx.Config.OpenAI.Seed,
+	for round := 0; ; round++ {
+		// 超过最大轮数后不再下发工具，要求模型直接作答
+		request.Tools = nil
+		if registry != nil && registry.Len() > 0 && round < l.svcCtx.Config.Tools.MaxRounds {
+			request.Tools = registry.Definitions()
 		}
 
 		// 4.创建流式响应
-		stream, err := l.svcCtx.OpenAIClient.CreateChatCompletionStream(l.ctx, request)
+		stream, err := l.svcCtx.LLM.CreateChatCompletionStream(ctx, request)
 		if err != nil {
+			if round > 0 {
+				finishUsage()
+			}
+			if ctx.Err() != nil {
+				l.abort(ctx, emit, gen, req.ChatId, fullResponse.String())
+				return
+			}
 			l.Logger.Error(err)
-			ch <- &types.ChatResponse{Content: "系统错误：无法连接AI服务", IsLast: true}
+			emit(sse.Error(sse.ErrCodeUpstream, "系统错误：无法连接AI服务"))
+			emit(sse.Done(sse.DoneReasonError))
 			return
 		}
-		defer stream.Close()
 
 		// 5.处理流式响应
-		var fullResponse strings.Builder
-		for {
-			select {
-			case <-l.ctx.Done():
+		roundStart := fullResponse.Len()
+		var roundUsage *openai.Usage
+		var generated strings.Builder // 模型输出的全部内容（含推理与工具参数），用于估算
+		toolCalls, err := l.readStream(ctx, stream, func(delta openai.ChatCompletionStreamChoiceDelta) {
+			generated.WriteString(delta.ReasoningContent + delta.Content)
+			answer, reasoning := think.Feed(delta.Content)
+			emitAnswer(answer, delta.ReasoningContent+reasoning)
+		}, func(u *openai.Usage) {
+			roundUsage = u
+		})
+		stream.Close()
+		for _, call := range toolCalls {
+			generated.WriteString(call.Function.Name + call.Function.Arguments)
+		}
+		accumulateUsage(&usage, stream, roundUsage, request.Messages, generated.String())
+
+		if err != nil {
+			finishUsage()
+			if ctx.Err() != nil {
+				l.abort(ctx, emit, gen, req.ChatId, fullResponse.String())
 				return
-			default:
-				response, err := stream.Recv()
-				if errors.Is(err, io.EOF) { // 流结束后处理状态更新
-					finalResponse := fullResponse.String()
-					// 流结束后保存会话
-					if finalResponse != "" {
-						// 保存AI回复
-						if saveErr := l.svcCtx.VectorStore.SaveMessage(
-							req.ChatId, openai.ChatMessageRoleAssistant, fullResponse.String()); saveErr != nil {
-							l.Logger.Errorf("save message failed: %v", saveErr)
-						}
-
-						// 更新状态
-						newState, err := stateManager.EvaluateAndUpdateState(req.ChatId, finalResponse)
-						if err != nil {
-							l.Logger.Errorf("evaluate and update state failed: %v", err)
-						} else {
-							l.Logger.Infof("evaluate and update state: %v", newState)
-						}
-					}
-					// 发送结束标记
-					ch <- &types.ChatResponse{IsLast: true}
-					return
-				}
-				if err != nil {
-					l.Logger.Error(err)
-					return
-				}
+			}
+			l.Logger.Error(err)
+			emit(sse.Error(sse.ErrCodeUpstream, err.Error()))
+			emit(sse.Done(sse.DoneReasonError))
+			return
+		}
+		if len(toolCalls) == 0 || registry == nil {
+			break
+		}
+
+		// 执行工具并回填结果，继续下一轮生成
+		request.Messages = append(request.Messages, openai.ChatCompletionMessage{
+			Role:      openai.ChatMessageRoleAssistant,
+			Content:   fullResponse.String()[roundStart:],
+			ToolCalls: toolCalls,
+		})
+		for _, call := range toolCalls {
+			result := registry.Execute(ctx, session, call)
+			l.Logger.Infof("tool call %s(%s) -> %s", call.Function.Name, call.Function.Arguments, utils.TruncateText(result, 200))
+			request.Messages = append(request.Messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    result,
+				Name:       call.Function.Name,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	// 流结束后处理状态更新
+	emitAnswer(think.Flush())
+	finishUsage()
+	finalResponse := fullResponse.String()
+	// 流结束后保存会话
+	if finalResponse != "" {
+		// 保存AI回复
+		if saveErr := l.svcCtx.VectorStore.SaveMessage(
+			req.ChatId, openai.ChatMessageRoleAssistant, finalResponse); saveErr != nil {
+			l.Logger.Errorf("save message failed: %v", saveErr)
+		}
 
-				if len(response.Choices) > 0 && response.Choices[0].Delta.Content != "" {
-					content := response.Choices[0].Delta.Content
-					fullResponse.WriteString(content) // 收集完整响应
-					ch <- &types.ChatResponse{
-						Content: content,
-						IsLast:  false,
-					}
+		// 更新状态，模型已通过工具显式切换状态时不再按回复评估
+		newState := session.State
+		if !stateSetByTool {
+			newState, err = stateManager.EvaluateAndUpdateState(req.ChatId, turn, finalResponse)
+			if err != nil {
+				l.Logger.Errorf("evaluate and update state failed: %v", err)
+			} else {
+				l.Logger.Infof("evaluate and update state: %v", newState)
+				if newState != currentState {
+					emit(sse.StateChanged(currentState, newState))
 				}
 			}
 		}
-	}()
 
-	return ch, nil
+		// 面试结束，清理会话附件知识
+		if newState == types.StateEnd {
+			if err := l.svcCtx.VectorStore.DeleteSessionKnowledge(req.ChatId); err != nil {
+				l.Logger.Errorf("delete session knowledge failed: %v", err)
+			}
+		}
+	}
+	// 发送结束标记
+	emit(sse.Done(sse.DoneReasonStop))
+}
+
+// readStream 读取一次流式响应，逐个转发增量并合并模型发起的工具调用
+func (l *ChatLogic) readStream(ctx context.Context, stream llm.Stream,
+	onDelta func(openai.ChatCompletionStreamChoiceDelta), onUsage func(*openai.Usage)) ([]openai.ToolCall, error) {
+	var calls []openai.ToolCall
+	for {
+		select {
+		case <-ctx.Done():
+			return calls, ctx.Err()
+		default:
+		}
+
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return finishToolCalls(calls), nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return calls, ctx.Err()
+			}
+			return calls, err
+		}
+
+		if response.Usage != nil {
+			onUsage(response.Usage)
+		}
+		if len(response.Choices) > 0 {
+			delta := response.Choices[0].Delta
+			onDelta(delta)
+			calls = mergeToolCalls(calls, delta.ToolCalls)
+		}
+	}
+}
+
+// mergeToolCalls 按 index 合并流式返回的工具调用片段（参数分多次返回）
+func mergeToolCalls(calls []openai.ToolCall, deltas []openai.ToolCall) []openai.ToolCall {
+	for _, d := range deltas {
+		i := len(calls) - 1
+		switch {
+		case d.Index != nil:
+			i = *d.Index
+		case d.ID != "" || i < 0:
+			i = len(calls)
+		}
+		for len(calls) <= i {
+			calls = append(calls, openai.ToolCall{Type: openai.ToolTypeFunction})
+		}
+
+		call := &calls[i]
+		if d.ID != "" {
+			call.ID = d.ID
+		}
+		if d.Type != "" {
+			call.Type = d.Type
+		}
+		call.Function.Name += d.Function.Name
+		call.Function.Arguments += d.Function.Arguments
+	}
+	return calls
+}
+
+// finishToolCalls 补全缺失的调用ID，去掉仅用于流式合并的 index
+func finishToolCalls(calls []openai.ToolCall) []openai.ToolCall {
+	for i := range calls {
+		calls[i].Index = nil
+		if calls[i].ID == "" {
+			calls[i].ID = fmt.Sprintf("call_%d", i)
+		}
+	}
+	return calls
+}
+
+// accumulateUsage 累加一次模型调用的用量，提供方未返回用量时按输入消息与输出内容估算
+func accumulateUsage(u *types.TokenUsage, stream llm.Stream, usage *openai.Usage,
+	prompt []openai.ChatCompletionMessage, generated string) {
+	u.Provider, u.Model = llm.StreamSource(stream)
+	if usage == nil {
+		usage = &openai.Usage{
+			PromptTokens:     llm.EstimateMessagesTokens(prompt),
+			CompletionTokens: llm.EstimateTokens(generated),
+		}
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+		u.Estimated = true
+	}
+	u.PromptTokens += usage.PromptTokens
+	u.CompletionTokens += usage.CompletionTokens
+	u.TotalTokens += usage.TotalTokens
+}
+
+// budgetClosing 预算用尽时的结束语
+const budgetClosing = "本次面试的时长已达到上限，感谢你的参与！面试到此结束，评估结果将在稍后反馈给你。"
+
+// checkBudget 检查会话token预算：用量达到预算的WrapUpRatio时切换到评估阶段，
+// 用尽时直接发送结束语并结束面试，返回本轮使用的状态及是否已结束
+func (l *ChatLogic) checkBudget(emit func(*sse.Event), chatId, currentState string) (string, bool) {
+	cfg := l.svcCtx.Config.Usage
+	if cfg.SessionTokenBudget <= 0 || currentState == types.StateEnd {
+		return currentState, false
+	}
+	summary, err := l.svcCtx.Usage.SessionUsage(chatId)
+	if err != nil {
+		l.Logger.Errorf("get session usage failed: %v", err)
+		return currentState, false
+	}
+
+	stateManager := NewStateManager(l.svcCtx)
+	switch {
+	case summary.TotalTokens >= cfg.SessionTokenBudget:
+		if err := stateManager.SetState(chatId, types.StateEnd); err != nil {
+			l.Logger.Errorf("set state failed: %v", err)
+		}
+		emit(sse.StateChanged(currentState, types.StateEnd))
+		emit(sse.Notice(sse.NoticeBudgetExhausted, "本场面试token预算已用尽"))
+		emit(sse.Token(budgetClosing))
+		if err := l.svcCtx.VectorStore.SaveMessage(chatId, openai.ChatMessageRoleAssistant, budgetClosing); err != nil {
+			l.Logger.Errorf("save message failed: %v", err)
+		}
+		if err := l.svcCtx.VectorStore.DeleteSessionKnowledge(chatId); err != nil {
+			l.Logger.Errorf("delete session knowledge failed: %v", err)
+		}
+		emit(sse.Done(sse.DoneReasonStop))
+		return types.StateEnd, true
+	case float64(summary.TotalTokens) >= float64(cfg.SessionTokenBudget)*cfg.WrapUpRatio && currentState != types.StateEvaluate:
+		if err := stateManager.SetState(chatId, types.StateEvaluate); err != nil {
+			l.Logger.Errorf("set state failed: %v", err)
+		}
+		emit(sse.StateChanged(currentState, types.StateEvaluate))
+		return types.StateEvaluate, false
+	}
+	return currentState, false
+}
+
+// abort 生成被取消或超时，默认不保存已生成的部分内容，取消时可指定保留
+func (l *ChatLogic) abort(ctx context.Context, emit func(*sse.Event), gen *svc.Generation, chatId, partial string) {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		l.Logger.Infof("generation cancelled")
+		if gen.KeepPartial() && partial != "" {
+			if err := l.svcCtx.VectorStore.SaveMessage(chatId, openai.ChatMessageRoleAssistant, partial); err != nil {
+				l.Logger.Errorf("save partial message failed: %v", err)
+			}
+		}
+		emit(sse.Done(sse.DoneReasonCancelled))
+		return
+	}
+	l.Logger.Errorf("generation aborted: %v", ctx.Err())
+	emit(sse.Error(sse.ErrCodeUpstream, "生成超时"))
+	emit(sse.Done(sse.DoneReasonError))
+}
+
+// send 向事件通道发送事件，客户端断开时返回false
+func (l *ChatLogic) send(ch chan<- *sse.Event, e *sse.Event) bool {
+	select {
+	case <-l.ctx.Done():
+		return false
+	case ch <- e:
+		return true
+	}
+}
+
+// toSources 将检索到的知识片段转换为来源事件
+func toSources(knowledge []types.KnowledgeChunk) []sse.Source {
+	sources := make([]sse.Source, 0, len(knowledge))
+	for _, k := range knowledge {
+		sources = append(sources, sse.Source{
+			ID:      k.ID,
+			Title:   k.Title,
+			Scope:   k.Scope,
+			Snippet: utils.TruncateText(k.Content, 100),
+
+			Collection: k.Collection,
+		})
+	}
+	return sources
 }
 
 // 获取会话历史
@@ -155,7 +622,7 @@ func (l *ChatLogic) getSessionHistory(chatId string, knowledge []types.Knowledge
 			systemMessage += fmt.Sprintf("\n[知识片段%d] %s：%s", i+1, k.Title, truncateContent)
 		}
 	}
-	fmt.Println("检索的数据", systemMessage)
+	l.Debugf("注入知识片段 %d 条", len(knowledge))
 
 	// 转换为OpenAI消息格式
 	messages := []openai.ChatCompletionMessage{
@@ -201,7 +668,11 @@ func (l *ChatLogic) buildMessageWithState(chatId, currentState string, knowledge
 		for i, k := range knowledge {
 			// 限制知识片段长度
 			truncateContent := utils.TruncateText(k.Content, 500)
-			systemMessage += fmt.Sprintf("\n[知识片段%d] %s：%s", i+1, k.Title, truncateContent)
+			label := "知识片段"
+			if k.Scope == types.KnowledgeScopeSession {
+				label = "候选人附件片段"
+			}
+			systemMessage += fmt.Sprintf("\n[%s%d] %s：%s", label, i+1, k.Title, truncateContent)
 		}
 	}
 
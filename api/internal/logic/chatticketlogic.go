@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ErrNotInterviewer 未携带或携带了错误的面试官令牌
+var ErrNotInterviewer = errors.New("invalid interviewer token")
+
+type ChatTicketLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 签发面试官票据（需携带 X-Interviewer-Token 头）
+func NewChatTicketLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ChatTicketLogic {
+	return &ChatTicketLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ChatTicketLogic) ChatTicket(req *types.ChatTicketReq, token string) (resp *types.ChatTicketResp, err error) {
+	if !l.svcCtx.IsInterviewer(token) {
+		return nil, ErrNotInterviewer
+	}
+	ticket, expiresAt := l.svcCtx.InterviewerTicket(req.ChatId)
+	return &types.ChatTicketResp{
+		Ticket:    ticket,
+		ExpiresAt: expiresAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package logic
+
+import (
+	"context"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ChatUsageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 会话token用量
+func NewChatUsageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ChatUsageLogic {
+	return &ChatUsageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ChatUsageLogic) ChatUsage(req *types.ChatUsageReq) (resp *types.ChatUsageResp, err error) {
+	summary, err := l.svcCtx.Usage.SessionUsage(req.ChatId)
+	if err != nil {
+		return nil, err
+	}
+	records, err := l.svcCtx.Usage.SessionTurns(req.ChatId)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := l.svcCtx.Config.Usage.SessionTokenBudget
+	remaining := int64(-1)
+	if budget > 0 {
+		remaining = max(budget-summary.TotalTokens, 0)
+	}
+
+	turns := make([]types.TurnUsage, 0, len(records))
+	for _, u := range records {
+		turns = append(turns, types.TurnUsage{
+			Turn:             u.Turn,
+			State:            u.State,
+			Provider:         u.Provider,
+			Model:            u.Model,
+			PromptTokens:     u.PromptTokens,
+			CompletionTokens: u.CompletionTokens,
+			TotalTokens:      u.TotalTokens,
+			Estimated:        u.Estimated,
+			CreatedAt:        u.CreatedAt.Unix(),
+		})
+	}
+
+	return &types.ChatUsageResp{
+		ChatId:           req.ChatId,
+		PromptTokens:     summary.PromptTokens,
+		CompletionTokens: summary.CompletionTokens,
+		TotalTokens:      summary.TotalTokens,
+		Budget:           budget,
+		Remaining:        remaining,
+		Turns:            turns,
+	}, nil
+}
@@ -0,0 +1,209 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"ai-gozero-agent/api/sse"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"golang.org/x/net/websocket"
+)
+
+type ChatWsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+
+	conn      *websocket.Conn
+	chatId    string
+	reasoning bool // 是否推送推理内容（仅面试官）
+	writeLock sync.Mutex
+}
+
+// WebSocket双向面试会话
+func NewChatWsLogic(ctx context.Context, svcCtx *svc.ServiceContext, conn *websocket.Conn, chatId string, reasoning bool) *ChatWsLogic {
+	return &ChatWsLogic{
+		Logger:    logx.WithContext(ctx),
+		ctx:       ctx,
+		svcCtx:    svcCtx,
+		conn:      conn,
+		chatId:    chatId,
+		reasoning: reasoning,
+	}
+}
+
+// Serve 处理WebSocket会话，直到连接断开或心跳超时；collections 非空时设置为会话检索的知识库集合
+func (l *ChatWsLogic) Serve(collections []string) {
+	ctx, cancel := context.WithCancel(l.ctx)
+	defer cancel()
+
+	if len(collections) > 0 {
+		if err := NewStateManager(l.svcCtx).SetCollections(l.chatId, collections); err != nil {
+			l.Logger.Errorf("set session collections failed: %v", err)
+		}
+	}
+
+	commands := make(chan *sse.Command)
+	go l.readLoop(ctx, cancel, commands)
+
+	cfg := l.svcCtx.Config.WebSocket
+	heartbeat := time.NewTicker(cfg.HeartbeatInterval)
+	defer heartbeat.Stop()
+	lastSeen := time.Now()
+
+	// 面试时长限制
+	var timeUp <-chan time.Time
+	if cfg.TimeLimit > 0 {
+		startedAt, err := NewStateManager(l.svcCtx).StartedAt(l.chatId)
+		if err != nil {
+			l.Logger.Errorf("get interview started time failed: %v", err)
+		}
+		timer := time.NewTimer(time.Until(startedAt.Add(cfg.TimeLimit)))
+		defer timer.Stop()
+		timeUp = timer.C
+	}
+
+	// 候选人长时间未作答提醒
+	var (
+		idle   *time.Timer
+		idleUp <-chan time.Time
+	)
+	if cfg.IdleReminder > 0 {
+		idle = time.NewTimer(cfg.IdleReminder)
+		defer idle.Stop()
+		idleUp = idle.C
+	}
+	resetIdle := func() {
+		if idle != nil {
+			idle.Reset(cfg.IdleReminder)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case cmd := <-commands:
+			lastSeen = time.Now()
+			l.handle(ctx, cmd)
+			if cmd.Type == sse.CommandMessage || cmd.Type == sse.CommandTyping {
+				resetIdle()
+			}
+		case <-heartbeat.C:
+			if time.Since(lastSeen) > 2*cfg.HeartbeatInterval {
+				l.Logger.Infof("websocket heartbeat timeout, chatId: %s", l.chatId)
+				return
+			}
+		case <-timeUp:
+			l.onTimeUp()
+		case <-idleUp:
+			if !l.svcCtx.Generations.Running(l.chatId) {
+				l.write(sse.Notice(sse.NoticeIdle, "请问还在吗？可以继续作答，或发送“跳过”进入下一题"))
+			}
+			resetIdle()
+		}
+	}
+}
+
+// readLoop 读取客户端指令，连接断开时取消会话
+func (l *ChatWsLogic) readLoop(ctx context.Context, cancel context.CancelFunc, commands chan<- *sse.Command) {
+	defer cancel()
+	for {
+		var frame []byte
+		if err := websocket.Message.Receive(l.conn, &frame); err != nil {
+			return
+		}
+
+		var cmd sse.Command
+		if err := json.Unmarshal(frame, &cmd); err != nil {
+			l.write(sse.Error(sse.ErrCodeBadRequest, "invalid command"))
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case commands <- &cmd:
+		}
+	}
+}
+
+func (l *ChatWsLogic) handle(ctx context.Context, cmd *sse.Command) {
+	switch cmd.Type {
+	case sse.CommandPing:
+		l.write(sse.Pong())
+	case sse.CommandTyping:
+		// 仅用于刷新活跃时间
+	case sse.CommandCancel:
+		cancelled, err := l.svcCtx.Generations.Cancel(l.chatId, cmd.KeepPartial)
+		if err != nil {
+			l.Logger.Errorf("cancel generation failed: %v", err)
+			l.write(sse.Error(sse.ErrCodeInternal, err.Error()))
+			return
+		}
+		if !cancelled {
+			l.write(sse.Error(sse.ErrCodeBadRequest, "no generation in progress"))
+		}
+	case sse.CommandMessage:
+		events, err := NewChatLogic(ctx, l.svcCtx).WithReasoning(l.reasoning).Chat(&types.InterViewAPPChatReq{
+			ChatId:  l.chatId,
+			Message: cmd.Message,
+		})
+		if errors.Is(err, ErrGenerationBusy) {
+			l.write(sse.Error(sse.ErrCodeBusy, err.Error()))
+			return
+		}
+		if err != nil {
+			l.Logger.Errorf("start chat failed: %v", err)
+			l.write(sse.Error(sse.ErrCodeInternal, err.Error()))
+			return
+		}
+		go func() {
+			for e := range events {
+				if err := l.write(e); err != nil {
+					return
+				}
+			}
+		}()
+	default:
+		l.write(sse.Error(sse.ErrCodeBadRequest, "unknown command: "+cmd.Type))
+	}
+}
+
+// onTimeUp 面试时间到，推送提示并进入评估阶段
+func (l *ChatWsLogic) onTimeUp() {
+	stateManager := NewStateManager(l.svcCtx)
+	state, err := stateManager.GetOrInitState(l.chatId)
+	if err != nil {
+		l.Logger.Errorf("get current state failed: %v", err)
+	}
+	if state == types.StateEvaluate || state == types.StateEnd {
+		return
+	}
+
+	if err := stateManager.SetState(l.chatId, types.StateEvaluate); err != nil {
+		l.Logger.Errorf("set state failed: %v", err)
+	} else {
+		l.write(sse.StateChanged(state, types.StateEvaluate))
+	}
+	l.write(sse.Notice(sse.NoticeTimeUp, "面试时间已到，接下来将进行总结评估"))
+}
+
+// write 串行写入WebSocket帧
+func (l *ChatWsLogic) write(e *sse.Event) error {
+	frame, err := sse.MarshalFrame(e)
+	if err != nil {
+		l.Logger.Errorf("marshal websocket frame failed: %v", err)
+		return err
+	}
+
+	l.writeLock.Lock()
+	defer l.writeLock.Unlock()
+	return websocket.Message.Send(l.conn, string(frame))
+}
@@ -0,0 +1,47 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DailyUsageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 每日token用量
+func NewDailyUsageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DailyUsageLogic {
+	return &DailyUsageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DailyUsageLogic) DailyUsage(req *types.DailyUsageReq) (resp *types.DailyUsageResp, err error) {
+	date := req.Date
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+
+	summary, sessions, err := l.svcCtx.Usage.DailyUsage(date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DailyUsageResp{
+		Date:             date,
+		PromptTokens:     summary.PromptTokens,
+		CompletionTokens: summary.CompletionTokens,
+		TotalTokens:      summary.TotalTokens,
+		Turns:            summary.Turns,
+		Sessions:         sessions,
+	}, nil
+}
@@ -0,0 +1,33 @@
+package logic
+
+import (
+	"context"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KnowledgeBatchLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 批量导入状态汇总
+func NewKnowledgeBatchLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeBatchLogic {
+	return &KnowledgeBatchLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *KnowledgeBatchLogic) KnowledgeBatch(req *types.KnowledgeBatchReq) (resp *types.KnowledgeImportResp, err error) {
+	batch, err := l.svcCtx.IngestJobs.GetBatch(l.ctx, req.BatchId)
+	if err != nil {
+		return nil, err
+	}
+	return knowledgeImportResp(batch), nil
+}
@@ -0,0 +1,37 @@
+package logic
+
+import (
+	"context"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KnowledgeCollectionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 知识库集合列表
+func NewKnowledgeCollectionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeCollectionsLogic {
+	return &KnowledgeCollectionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *KnowledgeCollectionsLogic) KnowledgeCollections() (resp *types.KnowledgeCollectionsResp, err error) {
+	collections, err := l.svcCtx.VectorStore.KnowledgeCollections(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp = &types.KnowledgeCollectionsResp{Collections: make([]types.KnowledgeCollection, len(collections))}
+	for i, c := range collections {
+		resp.Collections[i] = types.KnowledgeCollection{Name: c.Name, Documents: c.Documents, Chunks: c.Chunks}
+	}
+	return resp, nil
+}
@@ -0,0 +1,79 @@
+package logic
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"ai-gozero-agent/mcp/knowledgefilter"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KnowledgeExportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 导出知识库快照（gzip压缩的JSONL）
+func NewKnowledgeExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeExportLogic {
+	return &KnowledgeExportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// KnowledgeExport 将快照写入响应；开始输出前的错误返回给调用方，输出中途出错时只记录日志，
+// 客户端得到的快照不完整，导入时会因数量与文件头不一致而失败
+func (l *KnowledgeExportLogic) KnowledgeExport(req *types.KnowledgeExportReq, w http.ResponseWriter) error {
+	collections, err := svc.ParseCollections(req.Collections)
+	if err != nil {
+		return err
+	}
+	filter, err := knowledgefilter.Parse(req.Filter)
+	if err != nil {
+		return err
+	}
+	filter = knowledgefilter.In("collection", collections...).And(filter)
+
+	out := &startedWriter{w: w, header: func() {
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf(`attachment; filename="knowledge-%s.jsonl.gz"`, time.Now().Format("20060102-150405")))
+	}}
+	gz := gzip.NewWriter(out)
+	h, err := l.svcCtx.VectorStore.ExportKnowledge(l.ctx, gz, filter, req.Embeddings)
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		if !out.started {
+			return err
+		}
+		l.Logger.Errorf("export knowledge failed: %v", err)
+		return nil
+	}
+	l.Logger.Infof("exported knowledge snapshot: %d documents, %d chunks", h.Documents, h.Chunks)
+	return nil
+}
+
+// startedWriter 第一次写入时设置响应头，并记录响应是否已开始输出
+type startedWriter struct {
+	w       http.ResponseWriter
+	header  func()
+	started bool
+}
+
+func (s *startedWriter) Write(p []byte) (int, error) {
+	if !s.started {
+		s.header()
+		s.started = true
+	}
+	return s.w.Write(p)
+}
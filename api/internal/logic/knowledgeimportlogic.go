@@ -0,0 +1,79 @@
+package logic
+
+import (
+	"context"
+	"io"
+
+	"ai-gozero-agent/api/internal/importer"
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KnowledgeImportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 批量导入ZIP/tar归档
+func NewKnowledgeImportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeImportLogic {
+	return &KnowledgeImportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// KnowledgeImport 为归档中的每个文件创建导入任务；清单中的URL条目不在服务端下载，记为 rejected
+func (l *KnowledgeImportLogic) KnowledgeImport(req *types.KnowledgeImportReq, archive io.ReaderAt, size int64, manifest *importer.Manifest) (resp *types.KnowledgeImportResp, err error) {
+	im := importer.New(l.svcCtx.IngestJobs, l.svcCtx.Config.MCP.MaxFileSize)
+	im.MaxFiles = l.svcCtx.Config.Ingest.MaxBatchFiles
+	im.MaxBytes = l.svcCtx.Config.Ingest.MaxBatchBytes
+	im.Defaults = svc.KnowledgeMeta{
+		Collection: req.Collection,
+		Topic:      req.Topic,
+		Difficulty: req.Difficulty,
+		Language:   req.Language,
+		Tags:       svc.ParseTags(req.Tags),
+	}
+	batch, err := im.Archive(l.ctx, archive, size, manifest)
+	if err != nil {
+		l.Errorf("import knowledge archive failed: %v", err)
+		return nil, err
+	}
+
+	resp = knowledgeImportResp(batch)
+	l.Infof("knowledge batch %s: %d files queued, %d rejected", batch.ID, resp.Queued, resp.Rejected)
+	return resp, nil
+}
+
+func knowledgeImportResp(batch *svc.ImportBatch) *types.KnowledgeImportResp {
+	s := batch.Summary()
+	resp := &types.KnowledgeImportResp{
+		BatchId:   batch.ID,
+		Total:     s.Total,
+		Rejected:  s.Rejected,
+		Queued:    s.Queued,
+		Running:   s.Running,
+		Succeeded: s.Succeeded,
+		Failed:    s.Failed,
+		Cancelled: s.Cancelled,
+		Done:      s.Done(),
+		Items:     make([]types.KnowledgeImportItem, len(batch.Items)),
+	}
+	for i, item := range batch.Items {
+		resp.Items[i] = types.KnowledgeImportItem{
+			Path:        item.Path,
+			Title:       item.Title,
+			Collection:  item.Collection,
+			Tags:        item.Tags,
+			JobId:       item.JobID,
+			Status:      item.Status,
+			Error:       item.Error,
+			SavedChunks: item.Chunks,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// extractShare 解析阶段占总进度的百分比，其余为生成向量并入库
+const extractShare = 30
+
+type KnowledgeIngestLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 执行知识库导入任务
+func NewKnowledgeIngestLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeIngestLogic {
+	return &KnowledgeIngestLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// IngestKnowledge 导入任务的处理函数，供 IngestQueue worker 调用
+func IngestKnowledge(svcCtx *svc.ServiceContext) svc.IngestHandler {
+	return func(ctx context.Context, job *svc.IngestJob, file []byte, progress *svc.JobProgress) error {
+		return NewKnowledgeIngestLogic(ctx, svcCtx).Ingest(job, file, progress)
+	}
+}
+
+// Ingest 解析文档、分块并逐块生成向量入库；重试时分块结果不变则从上次已入库的知识块之后继续
+func (l *KnowledgeIngestLogic) Ingest(job *svc.IngestJob, file []byte, progress *svc.JobProgress) error {
+	failed := 0
+	res, err := l.svcCtx.Extractor.Extract(l.ctx, bytes.NewReader(file), job.Filename, int64(len(file)), svc.ExtractHooks{
+		OnPage: func(e *svc.PageEvent) {
+			if e.Error != "" {
+				failed++
+			}
+			progress.Extracting(e.Percent*extractShare/100, failed)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, p := range res.Failed {
+		l.Errorf("%s 第%d页解析失败: %s", job.Filename, p.Page, p.Error)
+	}
+
+	// 标题优先使用上传时指定的标题，其次为文档元数据中的标题，最后为文件名
+	title := job.Title
+	if title == "" {
+		title = res.Info.Title
+	}
+	if title == "" {
+		title = job.Filename
+	}
+
+	// 未指定语言时按文档内容识别
+	meta := job.Meta()
+	if meta.Language == "" {
+		meta.Language = utils.DetectLanguage(res.Content)
+	}
+
+	cfg := l.svcCtx.Config.VectorDB
+	chunks := utils.SplitText(res.Content, cfg.Knowledge.MaxChunkSize)
+	start := 0
+	if job.Chunks == len(chunks) {
+		start = job.Saved
+	}
+	progress.Saved(embedPercent(start, len(chunks)), len(chunks), start)
+	for i := start; i < len(chunks); i++ {
+		if err := l.ctx.Err(); err != nil {
+			return err
+		}
+		if err := l.svcCtx.VectorStore.SaveKnowledgeChunk(title, chunks[i], meta); err != nil {
+			return err
+		}
+		progress.Saved(embedPercent(i+1, len(chunks)), len(chunks), i+1)
+	}
+	l.Infof("knowledge job %s saved %d chunks, title: %s", job.ID, len(chunks)-start, title)
+	return nil
+}
+
+func embedPercent(saved, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return extractShare + float64(100-extractShare)*float64(saved)/float64(total)
+}
@@ -0,0 +1,34 @@
+package logic
+
+import (
+	"context"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KnowledgeJobCancelLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 取消导入任务
+func NewKnowledgeJobCancelLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeJobCancelLogic {
+	return &KnowledgeJobCancelLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *KnowledgeJobCancelLogic) KnowledgeJobCancel(req *types.KnowledgeJobReq) (resp *types.KnowledgeJobResp, err error) {
+	job, err := l.svcCtx.IngestJobs.Cancel(l.ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+	l.Infof("cancel knowledge job %s, status: %s", job.ID, job.Status)
+	return knowledgeJobResp(job), nil
+}
@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"context"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KnowledgeJobLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 知识库导入任务状态
+func NewKnowledgeJobLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeJobLogic {
+	return &KnowledgeJobLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *KnowledgeJobLogic) KnowledgeJob(req *types.KnowledgeJobReq) (resp *types.KnowledgeJobResp, err error) {
+	job, err := l.svcCtx.IngestJobs.Get(l.ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+	return knowledgeJobResp(job), nil
+}
+
+func knowledgeJobResp(job *svc.IngestJob) *types.KnowledgeJobResp {
+	return &types.KnowledgeJobResp{
+		JobId:       job.ID,
+		Title:       job.Title,
+		Filename:    job.Filename,
+		Collection:  job.Collection,
+		Topic:       job.Topic,
+		Difficulty:  job.Difficulty,
+		Language:    job.Language,
+		Tags:        job.TagList(),
+		Status:      job.Status,
+		Stage:       job.Stage,
+		Percent:     job.Percent,
+		Chunks:      job.Chunks,
+		SavedChunks: job.Saved,
+		FailedPages: job.FailedPages,
+		Attempts:    job.Attempts,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
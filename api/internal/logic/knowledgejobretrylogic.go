@@ -0,0 +1,34 @@
+package logic
+
+import (
+	"context"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KnowledgeJobRetryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 重试失败或已取消的导入任务
+func NewKnowledgeJobRetryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeJobRetryLogic {
+	return &KnowledgeJobRetryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *KnowledgeJobRetryLogic) KnowledgeJobRetry(req *types.KnowledgeJobReq) (resp *types.KnowledgeJobResp, err error) {
+	job, err := l.svcCtx.IngestJobs.Retry(l.ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+	l.Infof("retry knowledge job %s, status: %s", job.ID, job.Status)
+	return knowledgeJobResp(job), nil
+}
@@ -1,9 +1,7 @@
 package logic
 
 import (
-	"ai-gozero-agent/api/internal/utils"
 	"context"
-	"fmt"
 
 	"ai-gozero-agent/api/internal/svc"
 	"ai-gozero-agent/api/internal/types"
@@ -17,7 +15,7 @@ type KnowledgeUploadLogic struct {
 	svcCtx *svc.ServiceContext
 }
 
-// 知识库上传
+// 知识库上传（创建导入任务）
 func NewKnowledgeUploadLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KnowledgeUploadLogic {
 	return &KnowledgeUploadLogic{
 		Logger: logx.WithContext(ctx),
@@ -26,22 +24,23 @@ func NewKnowledgeUploadLogic(ctx context.Context, svcCtx *svc.ServiceContext) *K
 	}
 }
 
-func (l *KnowledgeUploadLogic) KnowledgeUpload(req *types.KnowledgeUploadReq) (resp *types.KnowledgeUploadResp, err error) {
-	fmt.Println("进入logic处理！！：")
-	// 分块处理知识库内容
-	chunks := utils.SplitText(req.Content, l.svcCtx.Config.VectorDB.Knowledge.MaxChunkSize)
-	fmt.Println("准备分块！！:")
-	// 保存每个分块
-	for _, chunk := range chunks {
-		if err := l.svcCtx.VectorStore.SaveKnowledge(req.Title, chunk, l.svcCtx.Config.VectorDB); err != nil {
-			logx.Errorf("save knowledge failed: %v", err)
-			return nil, err
-		}
+// KnowledgeUpload 保存文件并创建导入任务，解析、分块与生成向量由 worker 异步完成
+func (l *KnowledgeUploadLogic) KnowledgeUpload(req *types.KnowledgeUploadReq, filename string, file []byte) (resp *types.KnowledgeUploadResp, err error) {
+	job, err := l.svcCtx.IngestJobs.Enqueue(l.ctx, req.Title, filename, svc.KnowledgeMeta{
+		Collection: req.Collection,
+		Topic:      req.Topic,
+		Difficulty: req.Difficulty,
+		Language:   req.Language,
+		Tags:       svc.ParseTags(req.Tags),
+	}, file)
+	if err != nil {
+		l.Errorf("enqueue knowledge job failed: %v", err)
+		return nil, err
 	}
-	fmt.Println("保存保存结束！！:")
+	l.Infof("knowledge job %s queued, file: %s, size: %d", job.ID, filename, len(file))
 
 	return &types.KnowledgeUploadResp{
-		Msg:    "知识上传成功",
-		Chunks: len(chunks),
+		Msg:   "知识导入任务已创建",
+		JobId: job.ID,
 	}, nil
 }
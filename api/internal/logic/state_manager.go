@@ -1,20 +1,38 @@
 package logic
 
 import (
+	"ai-gozero-agent/api/internal/llm"
 	"ai-gozero-agent/api/internal/svc"
 	"ai-gozero-agent/api/internal/types"
+	"ai-gozero-agent/mcp/knowledgefilter"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
+	"github.com/sashabaranov/go-openai"
+	"github.com/zeromicro/go-zero/core/logx"
+	"io"
 	"strings"
 	"time"
 )
 
 const (
-	stateKeyPrefix = "chat_state:"
-	stateTTL       = 24 * time.Hour
+	stateKeyPrefix     = "chat_state:"
+	prevStateKeyPrefix = "chat_state_prev:"  // 最近一轮评估前的状态，用于重新生成时回滚
+	startedAtKeyPrefix = "chat_started_at:"  // 面试开始时间（unix秒）
+	collectionsPrefix  = "chat_collections:" // 会话选择的知识库集合，逗号分隔
+	stateTTL           = 24 * time.Hour
+	stateEvalTimeout   = 30 * time.Second
 )
 
+// stateTransitions 各状态允许转移到的状态
+var stateTransitions = map[string][]string{
+	types.StateStart:    {types.StateQuestion},
+	types.StateQuestion: {types.StateFollowUp, types.StateEvaluate},
+	types.StateFollowUp: {types.StateEvaluate, types.StateQuestion},
+	types.StateEvaluate: {types.StateEnd, types.StateQuestion},
+}
+
 type StateManager struct {
 	svcCtx *svc.ServiceContext
 }
@@ -53,14 +71,70 @@ func (sm *StateManager) SetState(chatId string, state string) error {
 	return nil
 }
 
+// StartedAt 获取面试开始时间，首次调用时记录为当前时间
+func (sm *StateManager) StartedAt(chatId string) (time.Time, error) {
+	key := startedAtKeyPrefix + chatId
+	now := time.Now()
+	if err := sm.svcCtx.Redis.SetNX(context.Background(), key, now.Unix(), stateTTL).Err(); err != nil {
+		return now, fmt.Errorf("redis setnx failed: %w", err)
+	}
+	ts, err := sm.svcCtx.Redis.Get(context.Background(), key).Int64()
+	if err != nil {
+		return now, fmt.Errorf("redis get failed: %w", err)
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// SetCollections 设置会话检索的知识库集合，覆盖按状态配置的集合
+func (sm *StateManager) SetCollections(chatId string, collections []string) error {
+	key := collectionsPrefix + chatId
+	if err := sm.svcCtx.Redis.Set(context.Background(), key, strings.Join(collections, ","), stateTTL).Err(); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+// Collections 本轮检索的知识库集合：会话指定的集合优先，其次为当前状态配置的集合，最后为 Knowledge.Collections；为空时检索全部集合
+func (sm *StateManager) Collections(chatId, state string) []string {
+	value, err := sm.svcCtx.Redis.Get(context.Background(), collectionsPrefix+chatId).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logx.Errorf("get session collections failed: %v", err)
+	}
+	if collections := svc.ParseTags(value); len(collections) > 0 {
+		return collections
+	}
+	cfg := sm.svcCtx.Config.VectorDB.Knowledge
+	if collections, ok := cfg.StateCollections[state]; ok {
+		return collections
+	}
+	return cfg.Collections
+}
+
+// KnowledgeFilter 本轮知识检索范围：Collections 选择的集合，并满足当前状态配置的过滤条件
+func (sm *StateManager) KnowledgeFilter(chatId, state string) *knowledgefilter.Filter {
+	return knowledgefilter.In("collection", sm.Collections(chatId, state)...).And(sm.svcCtx.StateFilters[state])
+}
+
 // EvaluateAndUpdateState 评估并更新状态（更智能的规则）
-func (sm *StateManager) EvaluateAndUpdateState(chatId, aiResponse string) (string, error) {
+func (sm *StateManager) EvaluateAndUpdateState(chatId string, turn int64, aiResponse string) (string, error) {
 	currentState, err := sm.GetOrInitState(chatId)
 	if err != nil {
 		return currentState, err
 	}
 
-	newState := sm.determineNewState(currentState, aiResponse)
+	newState, err := sm.evaluateByModel(chatId, turn, currentState, aiResponse)
+	if err != nil {
+		// 未配置模型评估或模型输出无效时使用关键词规则
+		if !errors.Is(err, errNoStateEvalProfile) {
+			logx.Errorf("evaluate state by model failed: %v", err)
+		}
+		newState = sm.determineNewState(currentState, aiResponse)
+	}
+
+	// 记录评估前的状态，重新生成本轮回答时回滚
+	if err := sm.SavePrevState(chatId, currentState); err != nil {
+		return currentState, err
+	}
 
 	if newState != currentState {
 		if err := sm.SetState(chatId, newState); err != nil {
@@ -70,6 +144,97 @@ func (sm *StateManager) EvaluateAndUpdateState(chatId, aiResponse string) (strin
 	return newState, nil
 }
 
+// SavePrevState 记录本轮变更前的状态，重新生成本轮回答时回滚
+func (sm *StateManager) SavePrevState(chatId, state string) error {
+	if err := sm.svcCtx.Redis.Set(context.Background(), prevStateKeyPrefix+chatId, state, stateTTL).Err(); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+// RollbackState 回滚到最近一轮评估前的状态
+func (sm *StateManager) RollbackState(chatId string) (string, error) {
+	prev, err := sm.svcCtx.Redis.Get(context.Background(), prevStateKeyPrefix+chatId).Result()
+	if err == redis.Nil {
+		return sm.GetOrInitState(chatId)
+	}
+	if err != nil {
+		return "", fmt.Errorf("redis get failed: %w", err)
+	}
+	if err := sm.SetState(chatId, prev); err != nil {
+		return "", err
+	}
+	return prev, nil
+}
+
+var errNoStateEvalProfile = errors.New("state_eval profile not configured")
+
+// evaluateByModel 使用 state_eval 模型参数判断下一状态，仅在配置了该任务时启用，用量计入会话预算
+func (sm *StateManager) evaluateByModel(chatId string, turn int64, currentState, aiResponse string) (string, error) {
+	if !sm.svcCtx.HasProfile(types.TaskStateEval) {
+		return "", errNoStateEvalProfile
+	}
+	candidates := stateTransitions[currentState]
+	if len(candidates) == 0 {
+		return currentState, nil
+	}
+
+	prompt := fmt.Sprintf("你负责判断Go语言面试的流程状态。当前状态：%s。\n"+
+		"根据面试官的最新回复判断下一状态，只能从以下选项中选择一个并只输出状态名：%s\n"+
+		"状态含义：question=提出新问题，follow_up=追问，evaluate=评估总结，end=结束面试。",
+		currentState, strings.Join(append([]string{currentState}, candidates...), ", "))
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompt},
+		{Role: openai.ChatMessageRoleUser, Content: aiResponse},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stateEvalTimeout)
+	defer cancel()
+	stream, err := sm.svcCtx.LLM.CreateChatCompletionStream(ctx, sm.svcCtx.ChatRequest(types.TaskStateEval, messages))
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var output strings.Builder
+	var usage *openai.Usage
+	defer func() {
+		u := types.TokenUsage{ChatId: chatId, Turn: turn, State: types.TaskStateEval}
+		accumulateUsage(&u, stream, usage, messages, output.String())
+		if err := sm.svcCtx.Usage.Record(u); err != nil {
+			logx.Errorf("record state eval usage failed: %v", err)
+		}
+	}()
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if resp.Usage != nil {
+			usage = resp.Usage
+		}
+		if len(resp.Choices) > 0 {
+			output.WriteString(resp.Choices[0].Delta.Content)
+		}
+	}
+
+	// 去除推理内容后取最后出现的合法状态名
+	answer := strings.ToLower(llm.StripThink(output.String()))
+	newState, last := "", -1
+	for _, state := range append([]string{currentState}, candidates...) {
+		if i := strings.LastIndex(answer, state); i > last {
+			newState, last = state, i
+		}
+	}
+	if newState == "" {
+		return "", fmt.Errorf("unexpected state eval output: %q", output.String())
+	}
+	return newState, nil
+}
+
 // determineNewState 状态转移决策逻辑
 func (sm *StateManager) determineNewState(currentState, aiResponse string) string {
 	lowerResponse := strings.ToLower(aiResponse)
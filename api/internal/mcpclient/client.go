@@ -0,0 +1,147 @@
+package mcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"ai-gozero-agent/api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type transport interface {
+	call(ctx context.Context, method string, params any, result any) error
+	notify(ctx context.Context, method string, params any) error
+	setProtocolVersion(v string)
+	closed() bool
+	close() error
+}
+
+// Client 单个MCP服务的连接，首次使用时建立连接并握手，连接断开或会话过期后自动重连
+type Client struct {
+	c        config.MCPServerConfig
+	onNotify func(method string)
+
+	mu sync.Mutex
+	t  transport
+}
+
+func newClient(c config.MCPServerConfig, onNotify func(method string)) (*Client, error) {
+	if (c.Command == "") == (c.URL == "") {
+		return nil, fmt.Errorf("mcp server %s: exactly one of Command and URL must be set", c.Name)
+	}
+	return &Client{c: c, onNotify: onNotify}, nil
+}
+
+// transport 返回已握手的连接
+func (c *Client) transport(ctx context.Context) (transport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.t != nil && !c.t.closed() {
+		return c.t, nil
+	}
+
+	var t transport
+	if c.c.Command != "" {
+		st, err := newStdioTransport(c.c, c.onNotify)
+		if err != nil {
+			return nil, fmt.Errorf("start mcp server %s: %w", c.c.Name, err)
+		}
+		t = st
+	} else {
+		t = newHTTPTransport(c.c, c.onNotify)
+	}
+
+	var result initializeResult
+	err := t.call(ctx, "initialize", map[string]any{
+		"protocolVersion": protocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": clientName, "version": clientVersion},
+	}, &result)
+	if err == nil {
+		t.setProtocolVersion(result.ProtocolVersion)
+		err = t.notify(ctx, "notifications/initialized", nil)
+	}
+	if err != nil {
+		_ = t.close()
+		return nil, fmt.Errorf("initialize mcp server %s: %w", c.c.Name, err)
+	}
+
+	logx.Infof("mcp server %s connected: %s %s, protocol %s",
+		c.c.Name, result.ServerInfo.Name, result.ServerInfo.Version, result.ProtocolVersion)
+	c.t = t
+	return t, nil
+}
+
+// reset 丢弃失效的连接，下次调用时重连
+func (c *Client) reset(t transport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.t == t {
+		_ = t.close()
+		c.t = nil
+	}
+}
+
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, c.c.Timeout)
+	defer cancel()
+
+	// 会话过期时请求未被处理，可以重连后重试一次
+	for attempt := 0; ; attempt++ {
+		t, err := c.transport(ctx)
+		if err != nil {
+			return err
+		}
+		err = t.call(ctx, method, params, result)
+		if errors.Is(err, errSessionExpired) || errors.Is(err, errClosed) {
+			c.reset(t)
+			if errors.Is(err, errSessionExpired) && attempt == 0 {
+				continue
+			}
+		}
+		return err
+	}
+}
+
+// listTools 获取全部工具（处理分页）
+func (c *Client) listTools(ctx context.Context) ([]toolInfo, error) {
+	var tools []toolInfo
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		var result listToolsResult
+		if err := c.call(ctx, "tools/list", params, &result); err != nil {
+			return nil, err
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+func (c *Client) callTool(ctx context.Context, name string, args json.RawMessage) (*CallResult, error) {
+	var result CallResult
+	err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": args}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.t != nil {
+		_ = c.t.close()
+		c.t = nil
+	}
+}
@@ -0,0 +1,199 @@
+package mcpclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"ai-gozero-agent/api/internal/config"
+)
+
+const (
+	headerSessionId       = "Mcp-Session-Id"
+	headerProtocolVersion = "Mcp-Protocol-Version"
+	maxErrorBody          = 512
+)
+
+// httpTransport streamable HTTP 传输：每条消息一个 POST，响应可以是 JSON 或 SSE 流
+type httpTransport struct {
+	url      string
+	headers  map[string]string
+	client   *http.Client
+	onNotify func(method string)
+	nextID   atomic.Int64
+
+	mu              sync.Mutex
+	sessionId       string
+	protocolVersion string
+}
+
+func newHTTPTransport(c config.MCPServerConfig, onNotify func(method string)) *httpTransport {
+	return &httpTransport{
+		url:      c.URL,
+		headers:  c.Headers,
+		client:   &http.Client{},
+		onNotify: onNotify,
+	}
+}
+
+func (t *httpTransport) call(ctx context.Context, method string, params any, result any) error {
+	id := t.nextID.Add(1)
+	resp, err := t.post(ctx, request{JSONRPC: jsonrpcVersion, ID: &id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// initialize 响应携带会话ID，后续请求需带上
+	if sid := resp.Header.Get(headerSessionId); sid != "" {
+		t.mu.Lock()
+		t.sessionId = sid
+		t.mu.Unlock()
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		msg, err := t.readEventStream(resp.Body, id)
+		if err != nil {
+			return err
+		}
+		return decodeResult(msg, result)
+	}
+
+	var msg message
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return fmt.Errorf("decode mcp response: %w", err)
+	}
+	return decodeResult(&msg, result)
+}
+
+// readEventStream 读取SSE流直到收到对应请求的响应，期间的通知交给 onNotify
+func (t *httpTransport) readEventStream(body io.Reader, id int64) (*message, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStdioMessage)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data:") {
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+
+		// 空行结束一个事件
+		var msg message
+		err := json.Unmarshal([]byte(data.String()), &msg)
+		data.Reset()
+		if err != nil {
+			continue
+		}
+		if msg.Method != "" {
+			if len(msg.ID) == 0 {
+				t.onNotify(msg.Method)
+			}
+			continue
+		}
+		if string(msg.ID) == strconv.FormatInt(id, 10) {
+			return &msg, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("mcp event stream ended without response")
+}
+
+func (t *httpTransport) notify(ctx context.Context, method string, params any) error {
+	resp, err := t.post(ctx, request{JSONRPC: jsonrpcVersion, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (t *httpTransport) post(ctx context.Context, req request) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(httpReq)
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound && t.hasSession() {
+		resp.Body.Close()
+		return nil, errSessionExpired
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, fmt.Errorf("mcp http status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+func (t *httpTransport) setHeaders(req *http.Request) {
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionId != "" {
+		req.Header.Set(headerSessionId, t.sessionId)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set(headerProtocolVersion, t.protocolVersion)
+	}
+}
+
+func (t *httpTransport) hasSession() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionId != ""
+}
+
+func (t *httpTransport) setProtocolVersion(v string) {
+	t.mu.Lock()
+	t.protocolVersion = v
+	t.mu.Unlock()
+}
+
+func (t *httpTransport) closed() bool {
+	return false
+}
+
+// close 通知服务端结束会话
+func (t *httpTransport) close() error {
+	if !t.hasSession() {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
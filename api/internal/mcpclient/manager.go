@@ -0,0 +1,145 @@
+package mcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"ai-gozero-agent/api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	retryInterval = 30 * time.Second // 工具发现失败后的重试间隔
+	maxToolName   = 64               // function calling 工具名长度上限
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Tool 外部MCP服务提供的工具
+type Tool struct {
+	Server        string
+	Name          string // 服务端工具名
+	QualifiedName string // 暴露给模型的工具名：{服务名}__{工具名}
+	Description   string
+	InputSchema   json.RawMessage // JSON Schema
+	client        *Client
+}
+
+// Call 调用工具
+func (t *Tool) Call(ctx context.Context, args json.RawMessage) (*CallResult, error) {
+	return t.client.callTool(ctx, t.Name, args)
+}
+
+type server struct {
+	name   string
+	client *Client
+	allow  []string
+
+	mu       sync.Mutex
+	tools    []*Tool
+	loaded   bool // 是否已成功发现工具
+	stale    bool // 收到 list_changed 通知，需要重新发现
+	loading  bool
+	failedAt time.Time
+}
+
+// Manager 管理全部外部MCP服务；工具在后台发现并缓存，获取工具列表不会阻塞对话
+type Manager struct {
+	servers []*server
+}
+
+// NewManager 创建并在后台连接配置的服务，配置无效的服务会被跳过
+func NewManager(configs []config.MCPServerConfig) *Manager {
+	m := &Manager{}
+	for _, c := range configs {
+		s := &server{name: c.Name, allow: c.Allow}
+		client, err := newClient(c, func(method string) {
+			if method == "notifications/tools/list_changed" {
+				s.mu.Lock()
+				s.stale = true
+				s.mu.Unlock()
+			}
+		})
+		if err != nil {
+			logx.Errorf("skip mcp server: %v", err)
+			continue
+		}
+		s.client = client
+		m.servers = append(m.servers, s)
+	}
+	m.Tools()
+	return m
+}
+
+// Tools 当前可用的工具；尚未发现、发现失败超过重试间隔或工具列表已变更的服务会在后台刷新
+func (m *Manager) Tools() []*Tool {
+	var tools []*Tool
+	for _, s := range m.servers {
+		s.mu.Lock()
+		tools = append(tools, s.tools...)
+		refresh := !s.loading && (s.stale || !s.loaded && time.Since(s.failedAt) > retryInterval)
+		if refresh {
+			s.loading = true
+			s.stale = false
+			go m.load(s)
+		}
+		s.mu.Unlock()
+	}
+	return tools
+}
+
+func (m *Manager) load(s *server) {
+	infos, err := s.client.listTools(context.Background())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loading = false
+	if err != nil {
+		s.failedAt = time.Now()
+		logx.Errorf("list tools of mcp server %s failed: %v", s.name, err)
+		return
+	}
+
+	tools := make([]*Tool, 0, len(infos))
+	for _, info := range infos {
+		if len(s.allow) > 0 && !slices.Contains(s.allow, info.Name) {
+			continue
+		}
+		schema := info.InputSchema
+		if len(schema) == 0 || string(schema) == "null" {
+			schema = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
+		tools = append(tools, &Tool{
+			Server:        s.name,
+			Name:          info.Name,
+			QualifiedName: qualifiedName(s.name, info.Name),
+			Description:   info.Description,
+			InputSchema:   schema,
+			client:        s.client,
+		})
+	}
+	s.tools = tools
+	s.loaded = true
+	logx.Infof("mcp server %s: %d tools available (%d discovered)", s.name, len(tools), len(infos))
+}
+
+// qualifiedName 生成符合 function calling 命名规则的工具名
+func qualifiedName(server, tool string) string {
+	name := invalidNameChars.ReplaceAllString(server+"__"+tool, "_")
+	if len(name) > maxToolName {
+		name = name[:maxToolName]
+	}
+	return name
+}
+
+// Close 断开全部服务
+func (m *Manager) Close() {
+	for _, s := range m.servers {
+		s.client.close()
+	}
+}
@@ -0,0 +1,96 @@
+// Package mcpclient 连接外部 Model Context Protocol 服务（stdio 命令或 streamable HTTP），
+// 发现其工具并代为调用
+package mcpclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	jsonrpcVersion  = "2.0"
+	protocolVersion = "2025-06-18"
+	clientName      = "ai-gozero-agent"
+	clientVersion   = "1.0.0"
+)
+
+var (
+	errClosed         = errors.New("mcp transport closed")
+	errSessionExpired = errors.New("mcp session expired")
+)
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      *int64 `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// message 服务端发来的消息：响应、通知或请求
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError 服务端返回的 JSON-RPC 错误
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+type initializeResult struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	ServerInfo      struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"serverInfo"`
+}
+
+type toolInfo struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+type listToolsResult struct {
+	Tools      []toolInfo `json:"tools"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+// Content 工具结果内容
+type Content struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// CallResult tools/call 结果，IsError 表示工具执行失败
+type CallResult struct {
+	Content           []Content       `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+}
+
+// Text 拼接文本内容，非文本内容以占位符表示
+func (r *CallResult) Text() string {
+	var text string
+	for i, c := range r.Content {
+		if i > 0 {
+			text += "\n"
+		}
+		if c.Type == "text" {
+			text += c.Text
+		} else {
+			text += fmt.Sprintf("[%s %s]", c.Type, c.MimeType)
+		}
+	}
+	return text
+}
@@ -0,0 +1,198 @@
+package mcpclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ai-gozero-agent/api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	maxStdioMessage = 16 << 20
+	stdioCloseWait  = 3 * time.Second
+)
+
+// stdioTransport 启动子进程，以换行分隔的JSON消息通过标准输入输出通信
+type stdioTransport struct {
+	name     string
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	onNotify func(method string)
+
+	writeMu sync.Mutex
+	nextID  atomic.Int64
+
+	mu      sync.Mutex
+	pending map[int64]chan *message
+	done    chan struct{}
+}
+
+func newStdioTransport(c config.MCPServerConfig, onNotify func(method string)) (*stdioTransport, error) {
+	cmd := exec.Command(c.Command, c.Args...)
+	cmd.Env = append(os.Environ(), c.Env...)
+	cmd.Stderr = &stderrLogger{name: c.Name}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	t := &stdioTransport{
+		name:     c.Name,
+		cmd:      cmd,
+		stdin:    stdin,
+		onNotify: onNotify,
+		pending:  make(map[int64]chan *message),
+		done:     make(chan struct{}),
+	}
+	go t.readLoop(stdout)
+	return t, nil
+}
+
+func (t *stdioTransport) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStdioMessage)
+	for scanner.Scan() {
+		var msg message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			logx.Errorf("mcp server %s sent invalid message: %v", t.name, err)
+			continue
+		}
+
+		switch {
+		case msg.Method != "" && len(msg.ID) > 0:
+			t.replyServerRequest(&msg)
+		case msg.Method != "":
+			t.onNotify(msg.Method)
+		default:
+			id, err := strconv.ParseInt(string(msg.ID), 10, 64)
+			if err != nil {
+				continue
+			}
+			t.mu.Lock()
+			ch := t.pending[id]
+			delete(t.pending, id)
+			t.mu.Unlock()
+			if ch != nil {
+				ch <- &msg
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		logx.Errorf("mcp server %s read failed: %v", t.name, err)
+	}
+	close(t.done)
+	_ = t.cmd.Wait()
+}
+
+// replyServerRequest 响应服务端发起的请求，只支持 ping
+func (t *stdioTransport) replyServerRequest(msg *message) {
+	reply := map[string]any{"jsonrpc": jsonrpcVersion, "id": msg.ID}
+	if msg.Method == "ping" {
+		reply["result"] = struct{}{}
+	} else {
+		reply["error"] = RPCError{Code: -32601, Message: "Method not found"}
+	}
+	_ = t.write(reply)
+}
+
+func (t *stdioTransport) write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (t *stdioTransport) call(ctx context.Context, method string, params any, result any) error {
+	id := t.nextID.Add(1)
+	ch := make(chan *message, 1)
+	t.mu.Lock()
+	t.pending[id] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(request{JSONRPC: jsonrpcVersion, ID: &id, Method: method, Params: params}); err != nil {
+		return errClosed
+	}
+
+	select {
+	case msg := <-ch:
+		return decodeResult(msg, result)
+	case <-t.done:
+		return errClosed
+	case <-ctx.Done():
+		_ = t.notify(context.Background(), "notifications/cancelled", map[string]any{"requestId": id, "reason": ctx.Err().Error()})
+		return ctx.Err()
+	}
+}
+
+func (t *stdioTransport) notify(ctx context.Context, method string, params any) error {
+	return t.write(request{JSONRPC: jsonrpcVersion, Method: method, Params: params})
+}
+
+func (t *stdioTransport) setProtocolVersion(string) {}
+
+func (t *stdioTransport) closed() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close 关闭标准输入让子进程退出，超时后强制结束
+func (t *stdioTransport) close() error {
+	_ = t.stdin.Close()
+	select {
+	case <-t.done:
+	case <-time.After(stdioCloseWait):
+		_ = t.cmd.Process.Kill()
+	}
+	return nil
+}
+
+func decodeResult(msg *message, result any) error {
+	if msg.Error != nil {
+		return msg.Error
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(msg.Result, result)
+}
+
+// stderrLogger 将子进程标准错误输出写入日志
+type stderrLogger struct {
+	name string
+}
+
+func (l *stderrLogger) Write(p []byte) (int, error) {
+	logx.Infof("mcp server %s: %s", l.name, p)
+	return len(p), nil
+}
@@ -0,0 +1,120 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"ai-gozero-agent/api/internal/config"
+	"ai-gozero-agent/mcp/extractor"
+)
+
+const (
+	BackendRemote = "remote" // 通过MCP服务（gRPC）解析
+	BackendLocal  = "local"  // 在api进程内解析
+)
+
+var (
+	// ErrFileTooLarge 文件超过 MCP.MaxFileSize
+	ErrFileTooLarge = errors.New("file too large")
+	// ErrNoText 解析成功但没有任何文本（如未配置OCR的扫描件）
+	ErrNoText = errors.New("no text extracted from file")
+)
+
+// UploadProgress 上传进度回调，total 为0表示大小未知
+type UploadProgress func(sent, total int64)
+
+// PageEvent 单页（EPUB为章节，其余格式为整篇）解析结果
+type PageEvent struct {
+	Page       int     // 页码，从1开始
+	Pages      int     // 文档总页数
+	Percent    float64 // 解析进度 0-100
+	Error      string  // 该页解析错误，其余页面仍会使用
+	OCR        bool    // 文本由OCR识别
+	Confidence float64 // OCR平均置信度 0-1
+}
+
+// PageError 解析失败的页面
+type PageError struct {
+	Page  int
+	Error string
+}
+
+// ExtractHooks 解析过程回调，均可为nil
+type ExtractHooks struct {
+	OnUpload UploadProgress   // 上传进度，仅远程解析有上传阶段
+	OnPage   func(*PageEvent) // 每页解析完成
+}
+
+// ExtractResult 文档解析结果，Content 中由OCR识别的页面带有置信度标注
+type ExtractResult struct {
+	Content  string
+	Info     *extractor.Info // 格式、标题、作者、页数与目录
+	Pages    int             // 解析的页数
+	Failed   []PageError     // 解析失败的页面
+	OCRPages []extractor.OCRPage
+}
+
+// DocExtractor 文档解析后端，本地与远程实现的错误语义一致：
+// 文件过大返回 ErrFileTooLarge，格式不支持或密码错误返回 extractor.ErrUnsupported、extractor.ErrPassword，
+// 单页失败记录在结果中并通过 OnPage 报告，全部页面失败或没有任何文本时返回错误
+type DocExtractor interface {
+	Extract(ctx context.Context, file io.ReaderAt, filename string, size int64, hooks ExtractHooks) (*ExtractResult, error)
+}
+
+// NewDocExtractor 按 MCP.Backend 选择解析后端
+func NewDocExtractor(c config.Config) DocExtractor {
+	if c.MCP.Backend == BackendLocal {
+		return newLocalExtractor(c.MCP.MaxFileSize, extractor.Layout(c.MCP.Layout))
+	}
+	if c.MCP.Endpoint == "" {
+		log.Fatal("MCP.Endpoint is required when MCP.Backend is remote")
+	}
+	return NewPdfClient(c.MCP.Endpoint, c.MCP.ChunkSize, c.MCP.MaxFileSize, c.MCP.Layout)
+}
+
+// pageCollector 汇总逐页结果，本地与远程解析共用
+type pageCollector struct {
+	onPage  func(*PageEvent)
+	content strings.Builder
+	result  ExtractResult
+}
+
+func (c *pageCollector) add(e *PageEvent, text string) {
+	c.result.Pages++
+	if e.Error != "" {
+		c.result.Failed = append(c.result.Failed, PageError{Page: e.Page, Error: e.Error})
+	} else if strings.TrimSpace(text) != "" {
+		// OCR识别的页面加标注，提示模型文本可能有误
+		if e.OCR {
+			c.result.OCRPages = append(c.result.OCRPages, extractor.OCRPage{Page: e.Page, Confidence: e.Confidence})
+			c.content.WriteString(ocrNote(e.Page, e.Confidence))
+			c.content.WriteString("\n")
+		}
+		c.content.WriteString(text)
+		c.content.WriteString("\n\n")
+	}
+	if c.onPage != nil {
+		c.onPage(e)
+	}
+}
+
+// finish 返回成功解析的页面文本，全部页面失败时返回第一个页面错误
+func (c *pageCollector) finish(info *extractor.Info) (*ExtractResult, error) {
+	if failed := len(c.result.Failed); failed > 0 && failed == c.result.Pages {
+		return nil, fmt.Errorf("全部%d页解析失败：%s", failed, c.result.Failed[0].Error)
+	}
+	if strings.TrimSpace(c.content.String()) == "" {
+		return nil, ErrNoText
+	}
+	c.result.Content = c.content.String()
+	c.result.Info = info
+	return &c.result, nil
+}
+
+func ocrNote(page int, confidence float64) string {
+	return fmt.Sprintf("[第%d页为扫描件，文本由OCR识别，置信度%.2f]", page, confidence)
+}
@@ -0,0 +1,141 @@
+package svc
+
+import (
+	"ai-gozero-agent/api/internal/config"
+	"ai-gozero-agent/api/sse"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	eventStreamKeyPrefix = "chat_events:" // chat_events:{chatId}:{turn} 每轮生成的事件流
+	turnSeqKeyPrefix     = "chat_turn:"   // chat_turn:{chatId} 会话当前轮次
+	turnTTL              = 24 * time.Hour
+)
+
+// ErrStreamExpired 事件流已过期或不存在
+var ErrStreamExpired = errors.New("event stream expired")
+
+// EventBuffer 基于Redis Stream缓存生成中的事件，支持断线后按Last-Event-ID续传
+type EventBuffer struct {
+	rdb    *redis.Client
+	ttl    time.Duration
+	maxLen int64
+	block  time.Duration
+}
+
+func NewEventBuffer(rdb *redis.Client, c config.StreamConfig) *EventBuffer {
+	return &EventBuffer{
+		rdb:    rdb,
+		ttl:    c.TTL,
+		maxLen: c.MaxLen,
+		block:  c.BlockTimeout,
+	}
+}
+
+// StartTurn 为会话开启新一轮生成，返回轮次号
+func (b *EventBuffer) StartTurn(chatId string) (int64, error) {
+	key := turnSeqKeyPrefix + chatId
+	turn, err := b.rdb.Incr(context.Background(), key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis incr turn failed: %w", err)
+	}
+	b.rdb.Expire(context.Background(), key, turnTTL)
+	return turn, nil
+}
+
+// CurrentTurn 获取会话最近一轮的轮次号
+func (b *EventBuffer) CurrentTurn(chatId string) (int64, error) {
+	turn, err := b.rdb.Get(context.Background(), turnSeqKeyPrefix+chatId).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, ErrStreamExpired
+	}
+	if err != nil {
+		return 0, fmt.Errorf("redis get turn failed: %w", err)
+	}
+	return turn, nil
+}
+
+// Append 追加事件到本轮事件流，并为事件设置可续传的ID
+func (b *EventBuffer) Append(chatId string, turn int64, e *sse.Event) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", e.Type, err)
+	}
+
+	ctx := context.Background()
+	key := streamKey(chatId, turn)
+	id, err := b.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: b.maxLen,
+		Approx: true,
+		Values: map[string]any{"type": e.Type, "data": data},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("redis xadd failed: %w", err)
+	}
+	b.rdb.Expire(ctx, key, b.ttl)
+	e.ID = FormatEventID(turn, id)
+	return nil
+}
+
+// Read 阻塞读取afterID之后的事件，超时无新事件时返回空切片
+func (b *EventBuffer) Read(ctx context.Context, chatId string, turn int64, afterID string) ([]*sse.Event, error) {
+	key := streamKey(chatId, turn)
+	streams, err := b.rdb.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{key, afterID},
+		Block:   b.block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		// 超时：区分无新事件与事件流已过期
+		if n, err := b.rdb.Exists(ctx, key).Result(); err == nil && n == 0 {
+			return nil, ErrStreamExpired
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis xread failed: %w", err)
+	}
+
+	var events []*sse.Event
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			typ, _ := msg.Values["type"].(string)
+			data, _ := msg.Values["data"].(string)
+			events = append(events, &sse.Event{
+				ID:   FormatEventID(turn, msg.ID),
+				Type: typ,
+				Data: json.RawMessage(data),
+			})
+		}
+	}
+	return events, nil
+}
+
+func streamKey(chatId string, turn int64) string {
+	return eventStreamKeyPrefix + chatId + ":" + strconv.FormatInt(turn, 10)
+}
+
+// FormatEventID 事件ID格式为 {turn}:{streamID}
+func FormatEventID(turn int64, streamID string) string {
+	return strconv.FormatInt(turn, 10) + ":" + streamID
+}
+
+// ParseEventID 解析Last-Event-ID，返回轮次号和Stream ID
+func ParseEventID(id string) (int64, string, error) {
+	turnStr, streamID, ok := strings.Cut(id, ":")
+	if !ok || streamID == "" {
+		return 0, "", fmt.Errorf("invalid event id: %q", id)
+	}
+	turn, err := strconv.ParseInt(turnStr, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid event id: %q", id)
+	}
+	return turn, streamID, nil
+}
@@ -0,0 +1,196 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	generationKeyPrefix = "chat_generation:" // chat_generation:{chatId} 进行中的生成：owner 登记实例，cancel 取消标记
+	generationLease     = 10 * time.Second   // 登记租期，实例异常退出后到期释放
+	generationPoll      = time.Second        // 续租并检查取消标记的间隔
+)
+
+const (
+	cancelDiscard     = 1 // 取消，不保留部分内容
+	cancelKeepPartial = 2 // 取消，保留已生成的部分内容
+)
+
+// ErrGenerationBusy 会话已有进行中的生成
+var ErrGenerationBusy = errors.New("上一轮回答尚未结束，可先取消")
+
+var (
+	// registerGenerationScript 会话没有进行中的生成时登记
+	registerGenerationScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'owner', ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+	// renewGenerationScript 续租本实例的登记，返回取消标记（0未取消），登记已不属于本实例时返回-1
+	renewGenerationScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then
+	return -1
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return tonumber(redis.call('HGET', KEYS[1], 'cancel') or '0')
+`)
+
+	// cancelGenerationScript 为进行中的生成设置取消标记，没有进行中的生成时返回0
+	cancelGenerationScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'cancel', ARGV[1])
+return 1
+`)
+
+	// releaseGenerationScript 注销本实例的登记，不误删新一轮
+	releaseGenerationScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+)
+
+// GenerationRegistry 记录进行中的生成，支持按会话取消。
+// 登记与取消标记保存在Redis中，任一实例收到的取消请求都能停止其他实例上的生成
+type GenerationRegistry struct {
+	rdb     *redis.Client
+	lock    sync.Mutex
+	running map[string]*Generation // chatId -> 本实例进行中的生成
+}
+
+// Generation 一轮进行中的生成
+type Generation struct {
+	owner       string
+	cancel      context.CancelFunc
+	keepPartial atomic.Bool
+	done        chan struct{}
+	once        sync.Once
+}
+
+// KeepPartial 取消时是否保留已生成的部分内容
+func (g *Generation) KeepPartial() bool {
+	return g.keepPartial.Load()
+}
+
+// stop 取消生成并记录是否保留部分内容
+func (g *Generation) stop(keepPartial bool) {
+	g.keepPartial.Store(keepPartial)
+	g.cancel()
+}
+
+func NewGenerationRegistry(rdb *redis.Client) *GenerationRegistry {
+	return &GenerationRegistry{
+		rdb:     rdb,
+		running: make(map[string]*Generation),
+	}
+}
+
+// TryRegister 登记会话的一轮生成，会话已有进行中的生成（包括其他实例上的）时返回 ErrGenerationBusy
+func (g *GenerationRegistry) TryRegister(chatId string, cancel context.CancelFunc) (*Generation, error) {
+	gen := &Generation{owner: newID(), cancel: cancel, done: make(chan struct{})}
+	ok, err := registerGenerationScript.Run(context.Background(), g.rdb, []string{generationKeyPrefix + chatId},
+		gen.owner, generationLease.Milliseconds()).Bool()
+	if err != nil {
+		return nil, fmt.Errorf("register generation failed: %w", err)
+	}
+	if !ok {
+		return nil, ErrGenerationBusy
+	}
+
+	g.lock.Lock()
+	g.running[chatId] = gen
+	g.lock.Unlock()
+
+	go g.watch(chatId, gen)
+	return gen, nil
+}
+
+// watch 定期续租登记，检查到取消标记时取消生成
+func (g *GenerationRegistry) watch(chatId string, gen *Generation) {
+	ticker := time.NewTicker(generationPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-gen.done:
+			return
+		case <-ticker.C:
+		}
+		flag, err := renewGenerationScript.Run(context.Background(), g.rdb, []string{generationKeyPrefix + chatId},
+			gen.owner, generationLease.Milliseconds()).Int()
+		if err != nil {
+			logx.Errorf("renew generation of chat %s failed: %v", chatId, err)
+			continue
+		}
+		switch flag {
+		case -1:
+			logx.Errorf("generation of chat %s lost its registration", chatId)
+			return
+		case cancelDiscard, cancelKeepPartial:
+			gen.stop(flag == cancelKeepPartial)
+			return
+		}
+	}
+}
+
+// Unregister 生成结束后注销，只注销同一次登记，避免误删新一轮
+func (g *GenerationRegistry) Unregister(chatId string, gen *Generation) {
+	gen.once.Do(func() { close(gen.done) })
+
+	g.lock.Lock()
+	if g.running[chatId] == gen {
+		delete(g.running, chatId)
+	}
+	g.lock.Unlock()
+
+	if err := releaseGenerationScript.Run(context.Background(), g.rdb, []string{generationKeyPrefix + chatId}, gen.owner).Err(); err != nil {
+		logx.Errorf("release generation of chat %s failed: %v", chatId, err)
+	}
+}
+
+// Cancel 取消会话进行中的生成，没有进行中的生成时返回false。
+// 生成在本实例时立即取消，在其他实例时由其在下次续租时取消
+func (g *GenerationRegistry) Cancel(chatId string, keepPartial bool) (bool, error) {
+	flag := cancelDiscard
+	if keepPartial {
+		flag = cancelKeepPartial
+	}
+	ok, err := cancelGenerationScript.Run(context.Background(), g.rdb, []string{generationKeyPrefix + chatId}, flag).Bool()
+	if err != nil {
+		return false, fmt.Errorf("cancel generation failed: %w", err)
+	}
+
+	g.lock.Lock()
+	gen, local := g.running[chatId]
+	g.lock.Unlock()
+	if local {
+		gen.stop(keepPartial)
+	}
+	return ok || local, nil
+}
+
+// Running 会话是否有进行中的生成（包括其他实例上的）
+func (g *GenerationRegistry) Running(chatId string) bool {
+	n, err := g.rdb.Exists(context.Background(), generationKeyPrefix+chatId).Result()
+	if err != nil {
+		logx.Errorf("check generation of chat %s failed: %v", chatId, err)
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		_, ok := g.running[chatId]
+		return ok
+	}
+	return n == 1
+}
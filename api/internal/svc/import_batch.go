@@ -0,0 +1,130 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const ingestBatchKeyPrefix = "knowledge_batch:" // knowledge_batch:{id} 批量导入的文件与任务ID
+
+// ItemRejected 文件未能入队（格式不支持、超过大小限制、下载失败等）
+const ItemRejected = "rejected"
+
+// ErrBatchNotFound 批次不存在或已过期
+var ErrBatchNotFound = errors.New("import batch not found")
+
+// ImportItem 批量导入中的一个文件
+type ImportItem struct {
+	Path       string   `json:"path"` // 归档或目录中的相对路径，或URL
+	Title      string   `json:"title,omitempty"`
+	Collection string   `json:"collection,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	JobID      string   `json:"jobId,omitempty"` // 为空表示未能入队
+	Status     string   `json:"status"`          // 任务状态，未能入队时为 rejected
+	Error      string   `json:"error,omitempty"`
+	Chunks     int      `json:"chunks,omitempty"` // 已入库的知识块数
+}
+
+// ImportBatch 一次批量导入，每个文件对应一个导入任务
+type ImportBatch struct {
+	ID        string       `json:"id"`
+	Items     []ImportItem `json:"items"`
+	CreatedAt int64        `json:"createdAt"`
+}
+
+// ImportSummary 批量导入各状态的文件数
+type ImportSummary struct {
+	Total     int
+	Rejected  int
+	Queued    int
+	Running   int
+	Succeeded int
+	Failed    int
+	Cancelled int
+}
+
+// Done 所有任务均已结束（成功、失败或取消）
+func (s ImportSummary) Done() bool {
+	return s.Queued == 0 && s.Running == 0
+}
+
+// Summary 按状态汇总
+func (b *ImportBatch) Summary() ImportSummary {
+	s := ImportSummary{Total: len(b.Items)}
+	for _, item := range b.Items {
+		switch item.Status {
+		case ItemRejected:
+			s.Rejected++
+		case JobQueued:
+			s.Queued++
+		case JobRunning:
+			s.Running++
+		case JobSucceeded:
+			s.Succeeded++
+		case JobFailed:
+			s.Failed++
+		case JobCancelled:
+			s.Cancelled++
+		}
+	}
+	return s
+}
+
+// SaveBatch 保存批次，保留时长与任务一致
+func (q *IngestQueue) SaveBatch(ctx context.Context, items []ImportItem) (*ImportBatch, error) {
+	batch := &ImportBatch{ID: newID(), Items: items, CreatedAt: time.Now().Unix()}
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("marshal import batch: %w", err)
+	}
+	if err := q.rdb.Set(ctx, ingestBatchKeyPrefix+batch.ID, data, q.cfg.JobTTL).Err(); err != nil {
+		return nil, fmt.Errorf("redis save import batch failed: %w", err)
+	}
+	return batch, nil
+}
+
+// GetBatch 查询批次，并以各任务的当前状态更新文件状态
+func (q *IngestQueue) GetBatch(ctx context.Context, id string) (*ImportBatch, error) {
+	data, err := q.rdb.Get(ctx, ingestBatchKeyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrBatchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get import batch failed: %w", err)
+	}
+	var batch ImportBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("unmarshal import batch: %w", err)
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, len(batch.Items))
+	_, err = q.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, item := range batch.Items {
+			if item.JobID != "" {
+				cmds[i] = pipe.HGetAll(ctx, ingestJobKeyPrefix+item.JobID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redis get batch jobs failed: %w", err)
+	}
+	for i, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		item := &batch.Items[i]
+		var job IngestJob
+		if len(cmd.Val()) == 0 || cmd.Scan(&job) != nil {
+			item.Status, item.Error = JobFailed, ErrJobNotFound.Error()
+			continue
+		}
+		item.Status, item.Error, item.Chunks = job.Status, job.Error, job.Saved
+	}
+	return &batch, nil
+}
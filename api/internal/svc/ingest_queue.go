@@ -0,0 +1,526 @@
+package svc
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-gozero-agent/api/internal/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	ingestStream        = "knowledge_jobs" // 待处理任务（Stream + 消费组）
+	ingestGroup         = "ingest"         // worker 消费组
+	ingestJobKeyPrefix  = "knowledge_job:" // knowledge_job:{id} 任务状态
+	ingestBlock         = 5 * time.Second  // worker 阻塞读取超时
+	ingestHeartbeat     = 5 * time.Second  // 执行中任务的心跳与取消检查间隔
+	ingestCleanInterval = time.Hour        // 清理过期上传文件的间隔
+)
+
+// 任务状态
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// 任务阶段
+const (
+	StageExtract = "extract" // 解析文档
+	StageEmbed   = "embed"   // 生成向量并入库
+)
+
+var (
+	// ErrJobNotFound 任务不存在或已过期
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobState 任务当前状态不允许该操作
+	ErrJobState = errors.New("operation not allowed in current job status")
+	// ErrJobFileExpired 上传的文件已过期，无法重试
+	ErrJobFileExpired = errors.New("job file expired, please upload again")
+)
+
+// IngestJob 知识库导入任务
+type IngestJob struct {
+	ID          string  `redis:"id"`
+	Title       string  `redis:"title"` // 为空时使用文档标题或文件名
+	Filename    string  `redis:"filename"`
+	File        string  `redis:"file"`       // 上传文件在 FileDir 中的文件名，任务成功后删除
+	Collection  string  `redis:"collection"` // 写入的知识集合
+	Topic       string  `redis:"topic"`
+	Difficulty  string  `redis:"difficulty"`
+	Language    string  `redis:"language"` // 为空时按内容识别
+	Source      string  `redis:"source"`   // 来源文档，默认为文件名
+	Tags        string  `redis:"tags"`     // 标签，逗号分隔，随知识块入库
+	Status      string  `redis:"status"`
+	Stage       string  `redis:"stage"`
+	Percent     float64 `redis:"percent"` // 总进度 0-100
+	Chunks      int     `redis:"chunks"`  // 知识块总数，解析完成后确定
+	Saved       int     `redis:"saved"`   // 已入库的知识块数，重试时从此处继续
+	FailedPages int     `redis:"failedPages"`
+	Attempts    int     `redis:"attempts"`
+	Seq         int     `redis:"seq"` // 每次入队加1，与队列消息中的序号不一致的消息已失效
+	Error       string  `redis:"error"`
+	CreatedAt   int64   `redis:"createdAt"` // unix秒
+	UpdatedAt   int64   `redis:"updatedAt"`
+}
+
+// IngestHandler 执行导入任务，ctx 在任务被取消时取消
+type IngestHandler func(ctx context.Context, job *IngestJob, file []byte, progress *JobProgress) error
+
+// IngestQueue 基于Redis Stream的知识库导入任务队列；worker 中断的任务在 StaleAfter 后由其他 worker 重新认领
+type IngestQueue struct {
+	rdb *redis.Client
+	cfg config.IngestConfig
+}
+
+func NewIngestQueue(rdb *redis.Client, c config.IngestConfig) *IngestQueue {
+	return &IngestQueue{rdb: rdb, cfg: c}
+}
+
+// transitionScript 状态为 ARGV[4..] 之一且序号为 ARGV[3]（为0时不检查）时改为 ARGV[1] 并更新时间，返回原状态；
+// 任务不存在、状态或序号不符时返回nil
+var transitionScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if not status then return nil end
+if ARGV[3] ~= '0' and redis.call('HGET', KEYS[1], 'seq') ~= ARGV[3] then return nil end
+for i = 4, #ARGV do
+	if status == ARGV[i] then
+		redis.call('HSET', KEYS[1], 'status', ARGV[1], 'updatedAt', ARGV[2])
+		return status
+	end
+end
+return nil
+`)
+
+// transition 按状态流转任务，seq 不为0时只流转该次入队的任务；状态或序号不符时返回 ErrJobState
+func (q *IngestQueue) transition(ctx context.Context, id string, seq int, to string, from ...string) error {
+	args := []any{to, time.Now().Unix(), seq}
+	for _, s := range from {
+		args = append(args, s)
+	}
+	err := transitionScript.Run(ctx, q.rdb, []string{ingestJobKeyPrefix + id}, args...).Err()
+	if errors.Is(err, redis.Nil) {
+		if _, getErr := q.Get(ctx, id); getErr != nil {
+			return getErr
+		}
+		return ErrJobState
+	}
+	if err != nil {
+		return fmt.Errorf("redis job transition failed: %w", err)
+	}
+	return nil
+}
+
+// TagList 任务的标签
+func (j *IngestJob) TagList() []string {
+	return ParseTags(j.Tags)
+}
+
+// Meta 知识块的元数据
+func (j *IngestJob) Meta() KnowledgeMeta {
+	return KnowledgeMeta{
+		Collection: j.Collection,
+		Topic:      j.Topic,
+		Difficulty: j.Difficulty,
+		Language:   j.Language,
+		Tags:       j.TagList(),
+		Source:     j.Source,
+	}
+}
+
+// ParseTags 解析逗号分隔的标签，去除空白与重复
+func ParseTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// Enqueue 将文件保存到 FileDir 并创建任务，Redis 中只记录文件名；元数据不合法（如集合名、难度）时返回错误
+func (q *IngestQueue) Enqueue(ctx context.Context, title, filename string, meta KnowledgeMeta, file []byte) (*IngestJob, error) {
+	meta, err := meta.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	job := &IngestJob{
+		ID:         newID(),
+		Title:      title,
+		Filename:   filename,
+		Collection: meta.Collection,
+		Topic:      meta.Topic,
+		Difficulty: meta.Difficulty,
+		Language:   meta.Language,
+		Source:     cmp.Or(meta.Source, filename),
+		Tags:       strings.Join(meta.Tags, ","),
+		Status:     JobQueued,
+		Seq:        1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	job.File = job.ID
+	if err := q.saveFile(job.File, file); err != nil {
+		return nil, err
+	}
+
+	jobKey := ingestJobKeyPrefix + job.ID
+	_, err = q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, jobKey, job)
+		pipe.Expire(ctx, jobKey, q.cfg.JobTTL)
+		pipe.XAdd(ctx, &redis.XAddArgs{Stream: ingestStream, Values: map[string]any{"job": job.ID, "seq": job.Seq}})
+		return nil
+	})
+	if err != nil {
+		os.Remove(q.filePath(job.File))
+		return nil, fmt.Errorf("redis enqueue job failed: %w", err)
+	}
+	return job, nil
+}
+
+func (q *IngestQueue) filePath(name string) string {
+	return filepath.Join(q.cfg.FileDir, name)
+}
+
+// saveFile 先写入临时文件再改名，worker 不会读到写了一半的文件
+func (q *IngestQueue) saveFile(name string, data []byte) error {
+	if err := os.MkdirAll(q.cfg.FileDir, 0o700); err != nil {
+		return fmt.Errorf("create ingest file dir: %w", err)
+	}
+	f, err := os.CreateTemp(q.cfg.FileDir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("save ingest file: %w", err)
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), q.filePath(name))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("save ingest file: %w", err)
+	}
+	return nil
+}
+
+// cleanFiles 删除超过 JobTTL 未更新的上传文件（任务已过期或中断写入的临时文件）
+func (q *IngestQueue) cleanFiles() {
+	entries, err := os.ReadDir(q.cfg.FileDir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logx.Errorf("read ingest file dir failed: %v", err)
+		}
+		return
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || time.Since(info.ModTime()) < q.cfg.JobTTL {
+			continue
+		}
+		if err := os.Remove(q.filePath(e.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logx.Errorf("remove expired ingest file %s failed: %v", e.Name(), err)
+		}
+	}
+}
+
+func newID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Get 查询任务
+func (q *IngestQueue) Get(ctx context.Context, id string) (*IngestJob, error) {
+	res := q.rdb.HGetAll(ctx, ingestJobKeyPrefix+id)
+	if err := res.Err(); err != nil {
+		return nil, fmt.Errorf("redis get job failed: %w", err)
+	}
+	if len(res.Val()) == 0 {
+		return nil, ErrJobNotFound
+	}
+	var job IngestJob
+	if err := res.Scan(&job); err != nil {
+		return nil, fmt.Errorf("scan job: %w", err)
+	}
+	return &job, nil
+}
+
+// Cancel 取消排队或执行中的任务，执行中的任务在下一次心跳时停止，已入库的知识块保留
+func (q *IngestQueue) Cancel(ctx context.Context, id string) (*IngestJob, error) {
+	if err := q.transition(ctx, id, 0, JobCancelled, JobQueued, JobRunning); err != nil {
+		return nil, err
+	}
+	return q.Get(ctx, id)
+}
+
+// Retry 重新排队失败或已取消的任务，从已入库的知识块之后继续
+func (q *IngestQueue) Retry(ctx context.Context, id string) (*IngestJob, error) {
+	job, err := q.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != JobFailed && job.Status != JobCancelled {
+		return nil, ErrJobState
+	}
+	if job.File == "" {
+		return nil, ErrJobFileExpired
+	}
+	// 刷新文件时间，重新计算保留时长
+	now := time.Now()
+	if err := os.Chtimes(q.filePath(job.File), now, now); errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrJobFileExpired
+	} else if err != nil {
+		return nil, fmt.Errorf("touch ingest file: %w", err)
+	}
+	if err := q.transition(ctx, id, 0, JobQueued, JobFailed, JobCancelled); err != nil {
+		return nil, err
+	}
+
+	jobKey := ingestJobKeyPrefix + id
+	seq, err := q.rdb.HIncrBy(ctx, jobKey, "seq", 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis incr job seq failed: %w", err)
+	}
+	_, err = q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, jobKey, "error", "", "attempts", 0)
+		pipe.Expire(ctx, jobKey, q.cfg.JobTTL)
+		pipe.XAdd(ctx, &redis.XAddArgs{Stream: ingestStream, Values: map[string]any{"job": id, "seq": seq}})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redis retry job failed: %w", err)
+	}
+	return q.Get(ctx, id)
+}
+
+// Start 启动 workers 个 worker 处理任务，workers 为0时不处理（仅入队）
+func (q *IngestQueue) Start(workers int, handle IngestHandler) {
+	if workers <= 0 {
+		return
+	}
+	err := q.rdb.XGroupCreateMkStream(context.Background(), ingestStream, ingestGroup, "0").Err()
+	if err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		logx.Errorf("create ingest consumer group failed: %v", err)
+		return
+	}
+
+	host, _ := os.Hostname()
+	for i := 0; i < workers; i++ {
+		consumer := host + "-" + strconv.Itoa(os.Getpid()) + "-" + strconv.Itoa(i)
+		go q.work(consumer, handle)
+	}
+	go func() {
+		for {
+			q.cleanFiles()
+			time.Sleep(ingestCleanInterval)
+		}
+	}()
+}
+
+func (q *IngestQueue) work(consumer string, handle IngestHandler) {
+	ctx := context.Background()
+	for {
+		msgs, err := q.next(ctx, consumer)
+		if err != nil {
+			logx.Errorf("read ingest jobs failed: %v", err)
+			time.Sleep(ingestBlock)
+			continue
+		}
+		for _, msg := range msgs {
+			id, _ := msg.Values["job"].(string)
+			seq, _ := strconv.Atoi(fmt.Sprint(msg.Values["seq"]))
+			// Redis 故障时不确认消息，StaleAfter 后由 worker 重新认领
+			if err := q.process(consumer, msg.ID, id, seq, handle); err != nil {
+				logx.Errorf("ingest job %s interrupted, will be reclaimed: %v", id, err)
+				continue
+			}
+			q.rdb.XAck(ctx, ingestStream, ingestGroup, msg.ID)
+			q.rdb.XDel(ctx, ingestStream, msg.ID)
+		}
+	}
+}
+
+// next 优先认领超过 StaleAfter 未确认的任务（worker 已中断），否则阻塞读取新任务
+func (q *IngestQueue) next(ctx context.Context, consumer string) ([]redis.XMessage, error) {
+	msgs, _, err := q.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   ingestStream,
+		Group:    ingestGroup,
+		MinIdle:  q.cfg.StaleAfter,
+		Start:    "0-0",
+		Count:    1,
+		Consumer: consumer,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) > 0 {
+		return msgs, nil
+	}
+
+	streams, err := q.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    ingestGroup,
+		Consumer: consumer,
+		Streams:  []string{ingestStream, ">"},
+		Count:    1,
+		Block:    ingestBlock,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range streams {
+		msgs = append(msgs, s.Messages...)
+	}
+	return msgs, nil
+}
+
+// process 执行一条任务消息，任务进入终态或消息已失效时返回nil，可确认消息；Redis 故障时返回错误
+func (q *IngestQueue) process(consumer, msgID, id string, seq int, handle IngestHandler) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 重试前的旧消息（如排队时取消后又重试）直接跳过
+	job, err := q.Get(ctx, id)
+	if errors.Is(err, ErrJobNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if job.Seq != seq {
+		return nil
+	}
+	// 已取消、已完成的任务直接跳过；worker 中断后重新认领的任务状态仍为 running
+	err = q.transition(ctx, id, seq, JobRunning, JobQueued, JobRunning)
+	if errors.Is(err, ErrJobState) || errors.Is(err, ErrJobNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	jobKey := ingestJobKeyPrefix + id
+	attempts, err := q.rdb.HIncrBy(ctx, jobKey, "attempts", 1).Result()
+	if err != nil {
+		return fmt.Errorf("redis incr job attempts failed: %w", err)
+	}
+	if attempts > int64(q.cfg.MaxAttempts) {
+		return q.finish(ctx, id, seq, fmt.Errorf("超过最大执行次数（%d次）", q.cfg.MaxAttempts))
+	}
+	if job, err = q.Get(ctx, id); err != nil {
+		return err
+	}
+	if job.File == "" {
+		return q.finish(ctx, id, seq, ErrJobFileExpired)
+	}
+	file, err := os.ReadFile(q.filePath(job.File))
+	if errors.Is(err, fs.ErrNotExist) {
+		return q.finish(ctx, id, seq, ErrJobFileExpired)
+	}
+	if err != nil {
+		return fmt.Errorf("read job file failed: %w", err)
+	}
+
+	go q.heartbeat(ctx, cancel, consumer, msgID, id, seq)
+	logx.Infof("ingest job %s started, file: %s, attempt: %d", id, job.Filename, attempts)
+	return q.finish(ctx, id, seq, handle(ctx, job, file, &JobProgress{q: q, id: id}))
+}
+
+// heartbeat 定期重置消息空闲时间以免被其他 worker 认领；任务被取消或已重新入队（序号变化）时取消 ctx
+func (q *IngestQueue) heartbeat(ctx context.Context, cancel context.CancelFunc, consumer, msgID, id string, seq int) {
+	ticker := time.NewTicker(ingestHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		q.rdb.XClaimJustID(ctx, &redis.XClaimArgs{
+			Stream:   ingestStream,
+			Group:    ingestGroup,
+			Consumer: consumer,
+			Messages: []string{msgID},
+		})
+		values, err := q.rdb.HMGet(ctx, ingestJobKeyPrefix+id, "status", "seq").Result()
+		if err != nil {
+			continue
+		}
+		status, _ := values[0].(string)
+		current, _ := values[1].(string)
+		if status != JobRunning || current != strconv.Itoa(seq) {
+			logx.Infof("ingest job %s %s (seq %s), stopping attempt of seq %d", id, status, current, seq)
+			cancel()
+			return
+		}
+	}
+}
+
+// finish 记录任务结果；任务已被取消或已重新入队时保持其当前状态。返回错误表示结果未能写入，消息不应确认
+func (q *IngestQueue) finish(ctx context.Context, id string, seq int, err error) error {
+	ctx = context.WithoutCancel(ctx)
+	to := JobSucceeded
+	if err != nil {
+		to = JobFailed
+	}
+	terr := q.transition(ctx, id, seq, to, JobRunning)
+	if errors.Is(terr, ErrJobState) || errors.Is(terr, ErrJobNotFound) {
+		return nil
+	}
+	if terr != nil {
+		return terr
+	}
+	if err == nil {
+		q.rdb.HSet(ctx, ingestJobKeyPrefix+id, "percent", 100)
+		if job, err := q.Get(ctx, id); err == nil && job.File != "" {
+			os.Remove(q.filePath(job.File))
+		}
+		logx.Infof("ingest job %s succeeded", id)
+		return nil
+	}
+	q.rdb.HSet(ctx, ingestJobKeyPrefix+id, "error", err.Error())
+	logx.Errorf("ingest job %s failed: %v", id, err)
+	return nil
+}
+
+// JobProgress 处理函数报告任务进度
+type JobProgress struct {
+	q  *IngestQueue
+	id string
+}
+
+// Extracting 解析阶段进度，failedPages 为解析失败的页数
+func (p *JobProgress) Extracting(percent float64, failedPages int) {
+	p.update("stage", StageExtract, "percent", percent, "failedPages", failedPages)
+}
+
+// Saved 入库阶段进度
+func (p *JobProgress) Saved(percent float64, chunks, saved int) {
+	p.update("stage", StageEmbed, "percent", percent, "chunks", chunks, "saved", saved)
+}
+
+func (p *JobProgress) update(values ...any) {
+	values = append(values, "updatedAt", time.Now().Unix())
+	if err := p.q.rdb.HSet(context.Background(), ingestJobKeyPrefix+p.id, values...).Err(); err != nil {
+		logx.Errorf("update ingest job %s progress failed: %v", p.id, err)
+	}
+}
@@ -0,0 +1,68 @@
+package svc
+
+import (
+	"ai-gozero-agent/api/internal/types"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+const (
+	askedQuestionsKeyPrefix = "chat_asked_questions:" // chat_asked_questions:{chatId} 本场已出过的题目ID
+	askedQuestionsTTL       = 24 * time.Hour
+)
+
+// ErrNoQuestion 题库中没有符合条件且未出过的题目
+var ErrNoQuestion = errors.New("no more questions")
+
+// InterviewStore 题库与面试评分
+type InterviewStore struct {
+	pool *pgxpool.Pool
+	rdb  *redis.Client
+}
+
+func NewInterviewStore(pool *pgxpool.Pool, rdb *redis.Client) *InterviewStore {
+	return &InterviewStore{pool: pool, rdb: rdb}
+}
+
+// NextQuestion 随机抽取一道本场未出过的题目，topic、difficulty为空时不限制
+func (s *InterviewStore) NextQuestion(chatId, topic, difficulty string) (*types.Question, error) {
+	key := askedQuestionsKeyPrefix + chatId
+	asked, err := s.rdb.SMembers(context.Background(), key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis smembers failed: %w", err)
+	}
+	if asked == nil {
+		asked = []string{} // nil 会编码为 NULL，导致 ANY 条件恒为空
+	}
+
+	sql := `SELECT id, topic, difficulty, content, reference_answer FROM question_bank
+		WHERE ($1 = '' OR topic = $1) AND ($2 = '' OR difficulty = $2) AND NOT (id::text = ANY($3))
+		ORDER BY random() LIMIT 1`
+	var q types.Question
+	err = s.pool.QueryRow(context.Background(), sql, topic, difficulty, asked).
+		Scan(&q.ID, &q.Topic, &q.Difficulty, &q.Content, &q.ReferenceAnswer)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoQuestion
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DB select question: %w", err)
+	}
+
+	if err := s.rdb.SAdd(context.Background(), key, q.ID).Err(); err != nil {
+		return nil, fmt.Errorf("redis sadd failed: %w", err)
+	}
+	s.rdb.Expire(context.Background(), key, askedQuestionsTTL)
+	return &q, nil
+}
+
+// RecordScore 保存一项评分
+func (s *InterviewStore) RecordScore(score types.InterviewScore) error {
+	sql := `INSERT INTO interview_score (chat_id, dimension, score, max_score, comment) VALUES ($1, $2, $3, $4, $5)`
+	_, err := s.pool.Exec(context.Background(), sql, score.ChatId, score.Dimension, score.Score, score.MaxScore, score.Comment)
+	return err
+}
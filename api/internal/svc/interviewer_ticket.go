@@ -0,0 +1,40 @@
+package svc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InterviewerTicket 为会话签发面试官票据：EventSource与WebSocket无法设置请求头，
+// 面试官先携带令牌请求票据，再以 ticket 参数连接；票据只对该会话有效且很快过期，不暴露令牌本身
+func (s *ServiceContext) InterviewerTicket(chatId string) (string, time.Time) {
+	expiresAt := time.Now().Add(s.Config.Reasoning.TicketTTL)
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return exp + "." + s.ticketSignature(chatId, exp), expiresAt
+}
+
+// IsInterviewerTicket 票据签名正确、未过期且属于该会话
+func (s *ServiceContext) IsInterviewerTicket(chatId, ticket string) bool {
+	if s.Config.Reasoning.InterviewerToken == "" {
+		return false
+	}
+	exp, sig, ok := strings.Cut(ticket, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.ticketSignature(chatId, exp)))
+}
+
+func (s *ServiceContext) ticketSignature(chatId, exp string) string {
+	mac := hmac.New(sha256.New, []byte(s.Config.Reasoning.InterviewerToken))
+	mac.Write([]byte(chatId + "\n" + exp))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,96 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ai-gozero-agent/mcp/knowledgefilter"
+)
+
+// DefaultCollection 未指定集合时知识写入的集合
+const DefaultCollection = "default"
+
+// ErrInvalidCollection 集合名不合法
+var ErrInvalidCollection = errors.New("集合名只能包含小写字母、数字、-与_，且以字母或数字开头，最长64个字符")
+
+var collectionPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// KnowledgeMeta 知识块的元数据，导入时随任务保存，检索时可按 knowledgefilter 表达式过滤
+type KnowledgeMeta struct {
+	Collection string   `json:"collection,omitempty"` // 所属集合，如 go-core、distributed-systems
+	Topic      string   `json:"topic,omitempty"`      // 主题，如 concurrency、gc
+	Difficulty string   `json:"difficulty,omitempty"` // 难度：junior、middle、senior
+	Language   string   `json:"language,omitempty"`   // 语言，如 zh、en，为空时按内容识别
+	Tags       []string `json:"tags,omitempty"`
+	Source     string   `json:"-"` // 来源文档：文件名、归档中的路径或URL
+}
+
+// Normalize 校验并规范化元数据，集合为空时为 DefaultCollection
+func (m KnowledgeMeta) Normalize() (KnowledgeMeta, error) {
+	var err error
+	if m.Collection, err = NormalizeCollection(m.Collection); err != nil {
+		return m, err
+	}
+	m.Topic = strings.ToLower(strings.TrimSpace(m.Topic))
+	m.Difficulty = strings.ToLower(strings.TrimSpace(m.Difficulty))
+	if m.Difficulty != "" && !slices.Contains(knowledgefilter.Difficulties, m.Difficulty) {
+		return m, fmt.Errorf("难度只能为 %s: %s", strings.Join(knowledgefilter.Difficulties, "、"), m.Difficulty)
+	}
+	m.Language = strings.ToLower(strings.TrimSpace(m.Language))
+	m.Tags = ParseTags(strings.Join(m.Tags, ","))
+	return m, nil
+}
+
+// NormalizeCollection 校验集合名，为空时返回 DefaultCollection
+func NormalizeCollection(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return DefaultCollection, nil
+	}
+	if !collectionPattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCollection, name)
+	}
+	return name, nil
+}
+
+// ParseCollections 解析逗号分隔的集合名，为空时返回nil（不限制集合）
+func ParseCollections(s string) ([]string, error) {
+	names := ParseTags(s)
+	for _, name := range names {
+		if !collectionPattern.MatchString(name) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCollection, name)
+		}
+	}
+	return names, nil
+}
+
+// KnowledgeCollection 集合及其知识量
+type KnowledgeCollection struct {
+	Name      string
+	Documents int64 // 不同标题的文档数
+	Chunks    int64
+}
+
+// KnowledgeCollections 列出知识库中的集合
+func (vs *VectorStore) KnowledgeCollections(ctx context.Context) ([]KnowledgeCollection, error) {
+	sql := `SELECT collection, COUNT(DISTINCT title), COUNT(*) FROM knowledge_base GROUP BY collection ORDER BY collection`
+	rows, err := vs.Pool.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("DB select knowledge collections: %w", err)
+	}
+	defer rows.Close()
+
+	var collections []KnowledgeCollection
+	for rows.Next() {
+		var c KnowledgeCollection
+		if err := rows.Scan(&c.Name, &c.Documents, &c.Chunks); err != nil {
+			return nil, fmt.Errorf("DB select knowledge collections: %w", err)
+		}
+		collections = append(collections, c)
+	}
+	return collections, rows.Err()
+}
@@ -0,0 +1,270 @@
+package svc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"ai-gozero-agent/mcp/knowledgefilter"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SnapshotVersion 知识库快照格式版本
+const SnapshotVersion = 1
+
+// ErrSnapshotIncomplete 快照被截断或知识块数与文件头不一致（导出中断），此前的知识块已导入
+var ErrSnapshotIncomplete = errors.New("知识库快照不完整")
+
+// SnapshotHeader 快照第一行，记录导出时的向量模型与维度，导入时据此判断能否直接使用快照中的向量
+type SnapshotHeader struct {
+	Version        int       `json:"version"`
+	EmbeddingModel string    `json:"embeddingModel"`
+	Dimension      int       `json:"dimension"`  // 向量维度，不含向量时为0
+	Embeddings     bool      `json:"embeddings"` // 是否包含向量
+	Documents      int       `json:"documents"`  // 按集合、来源与标题区分的文档数
+	Chunks         int       `json:"chunks"`
+	Filter         string    `json:"filter,omitempty"` // 导出时的过滤条件
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// SnapshotChunk 快照中的一个知识块，文件头之后每行一个
+type SnapshotChunk struct {
+	Title      string          `json:"title"`
+	Content    string          `json:"content"`
+	Collection string          `json:"collection"`
+	Topic      string          `json:"topic,omitempty"`
+	Difficulty string          `json:"difficulty,omitempty"`
+	Language   string          `json:"language,omitempty"`
+	Source     string          `json:"source,omitempty"`
+	Tags       []string        `json:"tags,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Embedding  json.RawMessage `json:"embedding,omitempty"`
+}
+
+// SnapshotImportOptions 导入选项
+type SnapshotImportOptions struct {
+	Reembed  bool                  // 忽略快照中的向量，全部重新生成
+	Progress func(done, total int) // 每处理一个知识块回调一次，可为nil
+}
+
+// SnapshotImportResult 导入结果
+type SnapshotImportResult struct {
+	Header   SnapshotHeader
+	Reembed  string // 重新生成向量的原因，为空表示直接使用快照中的向量
+	Imported int
+	Skipped  int // 已存在（集合、标题、来源与内容均相同）的知识块
+}
+
+// ExportKnowledge 将满足过滤条件的知识块导出为JSONL快照（第一行为 SnapshotHeader），withEmbeddings 为false时不导出向量
+func (vs *VectorStore) ExportKnowledge(ctx context.Context, w io.Writer, filter *knowledgefilter.Filter, withEmbeddings bool) (*SnapshotHeader, error) {
+	// 统计与导出在同一快照内读取，保证文件头的数量与内容一致
+	tx, err := vs.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("DB begin export: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	h := &SnapshotHeader{
+		Version:        SnapshotVersion,
+		EmbeddingModel: vs.EmbeddingModel,
+		Embeddings:     withEmbeddings,
+		Filter:         filter.String(),
+		CreatedAt:      time.Now(),
+	}
+	where, args := filter.Where(1)
+	var minDim, maxDim int
+	sql := `SELECT COUNT(*), COUNT(DISTINCT (collection, source, title)),
+		COALESCE(MIN(jsonb_array_length(embedding)), 0), COALESCE(MAX(jsonb_array_length(embedding)), 0)
+		FROM knowledge_base WHERE ` + where
+	if err := tx.QueryRow(ctx, sql, args...).Scan(&h.Chunks, &h.Documents, &minDim, &maxDim); err != nil {
+		return nil, fmt.Errorf("DB count knowledge: %w", err)
+	}
+	if withEmbeddings {
+		if minDim != maxDim {
+			return nil, fmt.Errorf("知识库中的向量维度不一致（%d 与 %d），请不导出向量并在导入时重新生成", minDim, maxDim)
+		}
+		h.Dimension = maxDim
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(h); err != nil {
+		return nil, err
+	}
+
+	columns := "title, content, collection, topic, difficulty, language, source, tags, created_at"
+	if withEmbeddings {
+		columns += ", embedding"
+	}
+	rows, err := tx.Query(ctx, `SELECT `+columns+` FROM knowledge_base WHERE `+where+` ORDER BY collection, source, title, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("DB select knowledge: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c SnapshotChunk
+		dest := []any{&c.Title, &c.Content, &c.Collection, &c.Topic, &c.Difficulty, &c.Language, &c.Source, &c.Tags, &c.CreatedAt}
+		if withEmbeddings {
+			dest = append(dest, &c.Embedding)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("DB select knowledge: %w", err)
+		}
+		if err := enc.Encode(&c); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("DB select knowledge: %w", err)
+	}
+	return h, bw.Flush()
+}
+
+// ImportKnowledge 导入JSONL快照（可gzip压缩）。快照不含向量、向量模型与当前配置不同或维度与已有知识不同时重新生成向量；
+// 已存在的知识块跳过，中断后重新导入同一快照会从未导入的知识块继续
+func (vs *VectorStore) ImportKnowledge(ctx context.Context, r io.Reader, opts SnapshotImportOptions) (*SnapshotImportResult, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("读取gzip快照失败: %w", err)
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+	dec := json.NewDecoder(br)
+
+	res := &SnapshotImportResult{}
+	h := &res.Header
+	if err := dec.Decode(h); err != nil {
+		return nil, fmt.Errorf("读取快照文件头失败: %w", err)
+	}
+	if h.Version != SnapshotVersion {
+		return nil, fmt.Errorf("不支持的快照版本 %d", h.Version)
+	}
+	var err error
+	if res.Reembed, err = vs.reembedReason(ctx, h, opts.Reembed); err != nil {
+		return nil, err
+	}
+
+	for n := 1; ; n++ {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		var c SnapshotChunk
+		if err := dec.Decode(&c); err == io.EOF {
+			break
+		} else if errors.Is(err, io.ErrUnexpectedEOF) {
+			return res, fmt.Errorf("%w：第%d个知识块被截断", ErrSnapshotIncomplete, n)
+		} else if err != nil {
+			return res, fmt.Errorf("快照第%d个知识块: %w", n, err)
+		}
+		imported, err := vs.importChunk(ctx, &c, h, res.Reembed != "")
+		if err != nil {
+			return res, fmt.Errorf("快照第%d个知识块: %w", n, err)
+		}
+		if imported {
+			res.Imported++
+		} else {
+			res.Skipped++
+		}
+		if opts.Progress != nil {
+			opts.Progress(n, h.Chunks)
+		}
+	}
+	if res.Imported+res.Skipped != h.Chunks {
+		return res, fmt.Errorf("%w：文件头记录 %d 个知识块，实际读取 %d 个", ErrSnapshotIncomplete, h.Chunks, res.Imported+res.Skipped)
+	}
+	return res, nil
+}
+
+// reembedReason 判断快照中的向量能否直接使用，需要重新生成时返回原因
+func (vs *VectorStore) reembedReason(ctx context.Context, h *SnapshotHeader, force bool) (string, error) {
+	switch {
+	case force:
+		return "指定重新生成向量", nil
+	case !h.Embeddings:
+		return "快照不含向量", nil
+	case h.EmbeddingModel != vs.EmbeddingModel:
+		return fmt.Sprintf("向量模型不同：快照为 %s，当前为 %s", h.EmbeddingModel, vs.EmbeddingModel), nil
+	}
+
+	// 已有知识的向量维度须与快照一致，知识库为空时以快照为准
+	var dim int
+	err := vs.Pool.QueryRow(ctx, `SELECT jsonb_array_length(embedding) FROM knowledge_base LIMIT 1`).Scan(&dim)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("DB select embedding dimension: %w", err)
+	}
+	if dim != 0 && dim != h.Dimension {
+		return fmt.Sprintf("向量维度不同：快照为 %d，当前为 %d", h.Dimension, dim), nil
+	}
+	return "", nil
+}
+
+// importChunk 校验并写入一个知识块，已存在时返回false
+func (vs *VectorStore) importChunk(ctx context.Context, c *SnapshotChunk, h *SnapshotHeader, reembed bool) (bool, error) {
+	if c.Content == "" {
+		return false, errors.New("内容为空")
+	}
+	meta, err := KnowledgeMeta{
+		Collection: c.Collection,
+		Topic:      c.Topic,
+		Difficulty: c.Difficulty,
+		Language:   c.Language,
+		Tags:       c.Tags,
+	}.Normalize()
+	if err != nil {
+		return false, err
+	}
+	if meta.Tags == nil {
+		meta.Tags = []string{}
+	}
+
+	// content_hash 为数据库生成的 md5(content)，按索引定位后再比较内容
+	var exists bool
+	sql := `SELECT EXISTS (SELECT 1 FROM knowledge_base
+		WHERE collection = $1 AND content_hash = md5($4) AND title = $2 AND source = $3 AND content = $4)`
+	if err := vs.Pool.QueryRow(ctx, sql, meta.Collection, c.Title, c.Source, c.Content).Scan(&exists); err != nil {
+		return false, fmt.Errorf("DB select knowledge: %w", err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	var embedding []float32
+	if reembed {
+		if embedding, err = vs.generateEmbedding(c.Content); err != nil {
+			return false, fmt.Errorf("generateEmbedding: %w", err)
+		}
+	} else {
+		if err := json.Unmarshal(c.Embedding, &embedding); err != nil {
+			return false, fmt.Errorf("无效的向量: %w", err)
+		}
+		if len(embedding) != h.Dimension {
+			return false, fmt.Errorf("向量维度为 %d，与文件头的 %d 不一致", len(embedding), h.Dimension)
+		}
+	}
+	embeddingJson, err := json.Marshal(embedding)
+	if err != nil {
+		return false, fmt.Errorf("marshal embedding: %w", err)
+	}
+
+	createdAt := c.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	sql = `INSERT INTO knowledge_base (title, content, embedding, collection, topic, difficulty, language, source, tags, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err = vs.Pool.Exec(ctx, sql, c.Title, c.Content, embeddingJson,
+		meta.Collection, meta.Topic, meta.Difficulty, meta.Language, c.Source, meta.Tags, createdAt)
+	if err != nil {
+		return false, fmt.Errorf("DB Insert Knowledge: %w", err)
+	}
+	return true, nil
+}
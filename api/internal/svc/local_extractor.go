@@ -0,0 +1,55 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"ai-gozero-agent/mcp/extractor"
+)
+
+// localExtractor 在api进程内解析，不经过MCP服务，不支持OCR
+type localExtractor struct {
+	maxSize int64
+	layout  extractor.Layout
+}
+
+func newLocalExtractor(maxSize int64, layout extractor.Layout) *localExtractor {
+	return &localExtractor{maxSize: maxSize, layout: layout}
+}
+
+func (e *localExtractor) Extract(ctx context.Context, file io.ReaderAt, filename string, size int64, hooks ExtractHooks) (*ExtractResult, error) {
+	if size > e.maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	c := &pageCollector{onPage: hooks.OnPage}
+	info, err := extractor.Extract(ctx, file, size, extractor.Options{Layout: e.layout}, func(s *extractor.Section) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page := &PageEvent{
+			Page:       s.Index,
+			Pages:      s.Total,
+			Percent:    float64(s.Seq) * 100 / float64(s.Count),
+			OCR:        s.OCR,
+			Confidence: s.Confidence,
+		}
+		if s.Err != nil {
+			page.Error = s.Err.Error()
+		}
+		c.add(page, s.Text)
+		return nil
+	})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if errors.Is(err, extractor.ErrUnsupported) || errors.Is(err, extractor.ErrPassword) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("文档解析失败：%w", err)
+	}
+	return c.finish(info)
+}
@@ -0,0 +1,66 @@
+package svc
+
+import (
+	"crypto/subtle"
+
+	"ai-gozero-agent/api/internal/llm"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ChatRequest 按面试状态或任务名构建对话请求，profile 未配置的参数沿用OpenAI段；
+// 配置了LLM.Providers时不使用OpenAI段的模型，profile 未指定模型时由各提供方使用默认模型
+func (s *ServiceContext) ChatRequest(profile string, messages []openai.ChatCompletionMessage) llm.Request {
+	def := s.Config.OpenAI
+	request := llm.Request{ChatCompletionRequest: openai.ChatCompletionRequest{
+		Messages:         messages,
+		Stream:           true,
+		MaxTokens:        def.MaxTokens,
+		TopP:             def.TopP,
+		FrequencyPenalty: def.FrequencyPenalty,
+		PresencePenalty:  def.PresencePenalty,
+		Seed:             def.Seed,
+	}}
+	if len(s.Config.LLM.Providers) == 0 {
+		request.Model = def.Model
+	}
+	if def.Temperature != 0 {
+		request.Temperature = &def.Temperature
+	}
+
+	p, ok := s.Config.Profiles[profile]
+	if !ok {
+		return request
+	}
+	if p.Model != "" {
+		request.Model = p.Model
+	}
+	if p.MaxTokens != 0 {
+		request.MaxTokens = p.MaxTokens
+	}
+	if p.Temperature != nil {
+		request.Temperature = p.Temperature
+	}
+	if p.TopP != nil {
+		request.TopP = *p.TopP
+	}
+	if p.FrequencyPenalty != nil {
+		request.FrequencyPenalty = *p.FrequencyPenalty
+	}
+	if p.PresencePenalty != nil {
+		request.PresencePenalty = *p.PresencePenalty
+	}
+	return request
+}
+
+// HasProfile 是否配置了指定状态或任务的模型参数
+func (s *ServiceContext) HasProfile(profile string) bool {
+	_, ok := s.Config.Profiles[profile]
+	return ok
+}
+
+// IsInterviewer 令牌与配置的面试官令牌一致时可接收推理内容，未配置令牌时任何人都不可接收
+func (s *ServiceContext) IsInterviewer(token string) bool {
+	expected := s.Config.Reasoning.InterviewerToken
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
@@ -1,20 +1,28 @@
 package svc
 
 import (
+	"ai-gozero-agent/mcp/extractor"
 	"ai-gozero-agent/mcp/types/mcp"
 	"context"
 	"errors"
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/zrpc"
-	"io/ioutil"
-	"mime/multipart"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
+// PdfClient 远程解析：分块上传到MCP服务（gRPC），由服务端逐页解析
 type PdfClient struct {
-	client mcp.PdfProcessorClient
+	client    mcp.PdfProcessorClient
+	chunkSize int
+	maxSize   int64
+	layout    string
 }
 
-func NewPdfClient(endpoint string) *PdfClient {
+func NewPdfClient(endpoint string, chunkSize int, maxSize int64, layout string) *PdfClient {
 	// 创建gRPC客户端连接
 	conn := zrpc.MustNewClient(zrpc.RpcClientConf{
 		Endpoints: []string{endpoint},
@@ -22,57 +30,163 @@ func NewPdfClient(endpoint string) *PdfClient {
 	})
 
 	return &PdfClient{
-		client: mcp.NewPdfProcessorClient(conn.Conn()),
+		client:    mcp.NewPdfProcessorClient(conn.Conn()),
+		chunkSize: chunkSize,
+		maxSize:   maxSize,
+		layout:    layout,
 	}
 }
 
-func (c *PdfClient) ExtractFile(file multipart.File, filename string) (string, error) {
-	// 创建gRPC流
-	stream, err := c.client.ExtractText(context.Background())
+// Extract 分块流式上传文件，解析过程中逐页回调；单页失败不影响整体
+func (c *PdfClient) Extract(ctx context.Context, file io.ReaderAt, filename string, size int64, hooks ExtractHooks) (*ExtractResult, error) {
+	if size > c.maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	stream, err := c.client.ExtractTextStream(ctx)
 	if err != nil {
 		logx.Errorf("gRPC连接失败: %v", err)
-		return "", err
+		return nil, err
+	}
+	// 服务端提前结束（如超过大小限制）时 Send 返回 io.EOF，原因在随后的事件中
+	if _, err := c.upload(stream, io.NewSectionReader(file, 0, size), filename, size, hooks.OnUpload); err != nil && err != io.EOF {
+		return nil, err
 	}
-	defer func() {
-		if err := stream.CloseSend(); err != nil {
-			logx.Errorf("关闭gRPC失败: %v", err)
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	col := &pageCollector{onPage: hooks.OnPage}
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			return nil, errors.New("解析未完成即结束")
 		}
-	}()
+		if err != nil {
+			logx.Errorf("文档解析错误: %v", err)
+			return nil, err
+		}
+		if e.Done {
+			if e.Error != "" {
+				return nil, remoteError(e.Error)
+			}
+			logx.Infof("文件 %s 解析完成，共 %d 页", filename, e.TotalPages)
+			return col.finish(documentInfo(e.Info))
+		}
+
+		col.add(&PageEvent{
+			Page:       int(e.Page),
+			Pages:      int(e.TotalPages),
+			Percent:    float64(e.Percent),
+			Error:      e.Error,
+			OCR:        e.Ocr,
+			Confidence: float64(e.Confidence),
+		}, e.Text)
+	}
+}
+
+// remoteError 将服务端返回的错误信息还原为与本地解析一致的错误
+func remoteError(msg string) error {
+	switch {
+	case msg == extractor.ErrUnsupported.Error():
+		return extractor.ErrUnsupported
+	case msg == extractor.ErrPassword.Error():
+		return extractor.ErrPassword
+	case strings.HasPrefix(msg, "文件超过大小限制"):
+		return ErrFileTooLarge
+	}
+	return errors.New(msg)
+}
+
+// documentInfo 转换服务端返回的文档信息
+func documentInfo(di *mcp.DocumentInfo) *extractor.Info {
+	if di == nil {
+		return &extractor.Info{}
+	}
+	info := &extractor.Info{
+		Format:   extractor.Format(di.Format),
+		Title:    di.Title,
+		Author:   di.Author,
+		Subject:  di.Subject,
+		Keywords: di.Keywords,
+		Creator:  di.Creator,
+		Producer: di.Producer,
+		Pages:    int(di.PageCount),
+		Outline:  outlineItems(di.Outline),
+	}
+	info.Created, _ = time.Parse(time.RFC3339, di.CreationDate)
+	info.Modified, _ = time.Parse(time.RFC3339, di.ModDate)
+	return info
+}
 
+func outlineItems(items []*mcp.OutlineItem) []*extractor.OutlineItem {
+	if len(items) == 0 {
+		return nil
+	}
+	out := make([]*extractor.OutlineItem, len(items))
+	for i, item := range items {
+		out[i] = &extractor.OutlineItem{Title: item.Title, Page: int(item.Page), Children: outlineItems(item.Children)}
+	}
+	return out
+}
+
+// upload 发送元数据并按固定大小分块发送文件，返回已发送字节数；服务端提前结束流时返回 io.EOF
+func (c *PdfClient) upload(stream mcp.PdfProcessor_ExtractTextStreamClient, file io.Reader, filename string, size int64, progress UploadProgress) (int64, error) {
 	// 发送元数据
 	if err := stream.Send(&mcp.PdfRequest{
 		Data: &mcp.PdfRequest_Metadate{
 			Metadate: &mcp.Metadata{
 				Filename: filename,
-				MineType: "application/pdf",
+				MineType: mimeType(filename),
+				Layout:   c.layout,
 			},
 		},
 	}); err != nil {
-		logx.Errorf("发送元数据失败: %v", err)
-		return "", err
+		return 0, sendError(err)
 	}
 
-	// 一次性发送整个文件（小文件直接发送）
-	fileData, err := ioutil.ReadAll(file)
-	if err != nil {
-		logx.Errorf("读取文件失败: %v", err)
-		return "", err
+	// 按固定大小分块发送
+	buf := make([]byte, c.chunkSize)
+	var sent int64
+	for {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 {
+			sent += int64(n)
+			if sent > c.maxSize {
+				_ = stream.CloseSend()
+				return sent, ErrFileTooLarge
+			}
+			if err := stream.Send(&mcp.PdfRequest{
+				Data: &mcp.PdfRequest_Chunks{Chunks: buf[:n]},
+			}); err != nil {
+				return sent, sendError(err)
+			}
+			if progress != nil {
+				progress(sent, size)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return sent, nil
+		}
+		if err != nil {
+			logx.Errorf("读取文件失败: %v", err)
+			_ = stream.CloseSend()
+			return sent, err
+		}
 	}
+}
 
-	if err := stream.Send(&mcp.PdfRequest{
-		Data: &mcp.PdfRequest_Chunks{
-			Chunks: fileData,
-		},
-	}); err != nil {
-		logx.Errorf("发送文件数据失败: %v", err)
-		return "", err
+// mimeType 按扩展名推断声明的类型，服务端以内容识别为准
+func mimeType(filename string) string {
+	if t := mime.TypeByExtension(filepath.Ext(filename)); t != "" {
+		return t
 	}
+	return "application/octet-stream"
+}
 
-	// 关闭发送并接收响应
-	resp, err := stream.CloseAndRecv()
-	if err != nil {
-		logx.Errorf("PDF解析错误: %v", err)
-		return "", errors.New(resp.Error)
+func sendError(err error) error {
+	if err != io.EOF {
+		logx.Errorf("发送文件数据失败: %v", err)
 	}
-	return resp.Content, nil
+	return err
 }
@@ -0,0 +1,18 @@
+package svc
+
+import (
+	"ai-gozero-agent/sandbox/coderunner"
+	"github.com/zeromicro/go-zero/zrpc"
+)
+
+// newSandboxClient 创建代码运行沙箱客户端，未配置地址时返回nil
+func newSandboxClient(endpoint string) coderunner.CodeRunner {
+	if endpoint == "" {
+		return nil
+	}
+	conn := zrpc.MustNewClient(zrpc.RpcClientConf{
+		Endpoints: []string{endpoint},
+		NonBlock:  true,
+	})
+	return coderunner.NewCodeRunner(conn)
+}
@@ -2,6 +2,10 @@ package svc
 
 import (
 	"ai-gozero-agent/api/internal/config"
+	"ai-gozero-agent/api/internal/llm"
+	"ai-gozero-agent/api/internal/mcpclient"
+	"ai-gozero-agent/mcp/knowledgefilter"
+	"ai-gozero-agent/sandbox/coderunner"
 	"context"
 	"fmt"
 	"github.com/redis/go-redis/v9"
@@ -13,24 +17,46 @@ import (
 // ServiceContext 是所有连接共用的，服务启动时初始化一次，整个生命周期内共享
 type ServiceContext struct {
 	Config       config.Config
-	OpenAIClient *openai.Client
+	OpenAIClient *openai.Client // 向量生成
+	LLM          *llm.Router    // 对话生成（多提供方故障转移）
 	//SessionStore types.SessionStore // 会话存储
 	VectorStore *VectorStore
-	PdfClient   *PdfClient
+	Extractor   DocExtractor          // 文档解析（本地或MCP服务）
+	Sandbox     coderunner.CodeRunner // 代码运行沙箱，未配置时为nil
+	MCPTools    *mcpclient.Manager    // 外部MCP服务工具
 	Redis       *redis.Client
+	EventBuffer *EventBuffer        // 生成事件缓存（断线续传）
+	Generations *GenerationRegistry // 进行中的生成（取消）
+	Usage       *UsageTracker       // token用量统计
+	Interview   *InterviewStore     // 题库与评分
+	IngestJobs  *IngestQueue        // 知识库导入任务
+
+	StateFilters map[string]*knowledgefilter.Filter // 面试状态 -> 知识检索过滤条件
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
-	// 创建OpenAI客户端
-	//openaiConf := openai.DefaultConfig(c.OpenAI.ApiKey)
-	//openaiConf.BaseURL = c.OpenAI.BaseURL
-	//openAIClient := openai.NewClientWithConfig(openaiConf)
-
-	// ollama
-	openaiConf := openai.DefaultConfig("")
+	// 创建OpenAI客户端（向量生成），ollama 的 ApiKey 留空即可
+	openaiConf := openai.DefaultConfig(c.OpenAI.ApiKey)
 	openaiConf.BaseURL = c.OpenAI.BaseURL
 	openAIClient := openai.NewClientWithConfig(openaiConf)
 
+	// 大模型提供方，未配置时使用OpenAI段（不设置默认模型，由请求按状态指定）
+	llmConf := c.LLM
+	if len(llmConf.Providers) == 0 {
+		llmConf.Providers = []config.LLMProvider{{
+			Name:    "default",
+			Type:    llm.TypeOpenAI,
+			BaseURL: c.OpenAI.BaseURL,
+			ApiKey:  c.OpenAI.ApiKey,
+
+			IncludeUsage: true,
+		}}
+	}
+	llmRouter, err := llm.NewRouter(llmConf)
+	if err != nil {
+		log.Fatalf("NewRouter err: %v", err)
+	}
+
 	// 初始化向量存储
 	vectorStore, err := NewVectorStore(c.VectorDB, openAIClient)
 	if err != nil {
@@ -43,6 +69,7 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	} else {
 		log.Println("TestConnection success")
 	}
+	vectorStore.StartSessionKnowledgeJanitor(c.VectorDB.Knowledge.SessionTTL)
 
 	err = license.SetMeteredKey(c.UniPDFLicense)
 	if err != nil {
@@ -63,12 +90,30 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		log.Println("rdb.Ping success")
 	}
 
+	// 按状态配置的知识检索过滤条件，启动时校验
+	stateFilters := make(map[string]*knowledgefilter.Filter)
+	for state, expr := range c.VectorDB.Knowledge.StateFilters {
+		if stateFilters[state], err = knowledgefilter.Parse(expr); err != nil {
+			log.Fatalf("invalid Knowledge.StateFilters[%s]: %v", state, err)
+		}
+	}
+
 	return &ServiceContext{
 		Config:       c,
 		OpenAIClient: openAIClient,
+		LLM:          llmRouter,
 		//SessionStore: NewMemorySessionStore(), // 内存会话存储
 		VectorStore: vectorStore,
-		PdfClient:   NewPdfClient(c.MCP.Endpoint),
+		Extractor:   NewDocExtractor(c),
+		Sandbox:     newSandboxClient(c.Sandbox.Endpoint),
+		MCPTools:    mcpclient.NewManager(c.Tools.MCPServers),
 		Redis:       rdb,
+		EventBuffer: NewEventBuffer(rdb, c.Stream),
+		Generations: NewGenerationRegistry(rdb),
+		Usage:       NewUsageTracker(vectorStore.Pool, rdb, c.Usage),
+		Interview:   NewInterviewStore(vectorStore.Pool, rdb),
+		IngestJobs:  NewIngestQueue(rdb, c.Ingest),
+
+		StateFilters: stateFilters,
 	}
 }
@@ -0,0 +1,215 @@
+package svc
+
+import (
+	"ai-gozero-agent/api/internal/config"
+	"ai-gozero-agent/api/internal/types"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const (
+	usageSessionKeyPrefix  = "usage_session:"   // usage_session:{chatId} 会话用量汇总
+	usageDayKeyPrefix      = "usage_day:"       // usage_day:{yyyy-mm-dd} 每日用量汇总
+	usageDaySessionsPrefix = "usage_day_chats:" // usage_day_chats:{yyyy-mm-dd} 每日会话数（HyperLogLog）
+	usageSessionTTL        = 24 * time.Hour
+	usageDateLayout        = "2006-01-02"
+)
+
+// UsageTracker 记录每轮token用量到数据库，并在Redis中按会话、按天汇总
+type UsageTracker struct {
+	pool           *pgxpool.Pool
+	rdb            *redis.Client
+	dailyRetention time.Duration
+}
+
+func NewUsageTracker(pool *pgxpool.Pool, rdb *redis.Client, c config.UsageConfig) *UsageTracker {
+	return &UsageTracker{
+		pool:           pool,
+		rdb:            rdb,
+		dailyRetention: c.DailyRetention,
+	}
+}
+
+// Record 记录一轮用量
+func (t *UsageTracker) Record(u types.TokenUsage) error {
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = time.Now()
+	}
+
+	sql := `INSERT INTO token_usage (chat_id, turn, state, provider, model, prompt_tokens, completion_tokens, total_tokens, estimated, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	if _, err := t.pool.Exec(context.Background(), sql, u.ChatId, u.Turn, u.State, u.Provider, u.Model,
+		u.PromptTokens, u.CompletionTokens, u.TotalTokens, u.Estimated, u.CreatedAt); err != nil {
+		return fmt.Errorf("insert token usage: %w", err)
+	}
+
+	// 状态评估等辅助调用计入token用量，不计入生成轮数
+	var turns int64 = 1
+	if u.State == types.TaskStateEval {
+		turns = 0
+	}
+
+	ctx := context.Background()
+	date := u.CreatedAt.Format(usageDateLayout)
+	dayKey := usageDayKeyPrefix + date
+	pipe := t.rdb.TxPipeline()
+	pipe.HIncrBy(ctx, dayKey, "prompt", int64(u.PromptTokens))
+	pipe.HIncrBy(ctx, dayKey, "completion", int64(u.CompletionTokens))
+	pipe.HIncrBy(ctx, dayKey, "total", int64(u.TotalTokens))
+	pipe.HIncrBy(ctx, dayKey, "turns", turns)
+	pipe.Expire(ctx, dayKey, t.dailyRetention)
+	pipe.PFAdd(ctx, usageDaySessionsPrefix+date, u.ChatId)
+	pipe.Expire(ctx, usageDaySessionsPrefix+date, t.dailyRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis incr usage failed: %w", err)
+	}
+	return t.incrSession(ctx, u, turns)
+}
+
+// usageIncrScript 会话汇总存在时累加并续期，返回1；不存在时不创建，返回0
+var usageIncrScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then return 0 end
+local fields = {'prompt', 'completion', 'total', 'turns'}
+for i, f in ipairs(fields) do
+	redis.call('HINCRBY', KEYS[1], f, ARGV[i])
+end
+redis.call('EXPIRE', KEYS[1], ARGV[5])
+return 1
+`)
+
+// usageSeedScript 用数据库统计值重建会话汇总，每个字段取已有值与统计值中的较大者，避免并发重建时互相覆盖
+var usageSeedScript = redis.NewScript(`
+local fields = {'prompt', 'completion', 'total', 'turns'}
+for i, f in ipairs(fields) do
+	local cur = tonumber(redis.call('HGET', KEYS[1], f) or '0')
+	if tonumber(ARGV[i]) > cur then
+		redis.call('HSET', KEYS[1], f, ARGV[i])
+	end
+end
+redis.call('EXPIRE', KEYS[1], ARGV[5])
+return 1
+`)
+
+// incrSession 累加会话汇总；汇总已过期时从数据库（已包含本次记录）重新统计，
+// 不能直接 HINCRBY，否则过期后从零开始累计会低估会话用量
+func (t *UsageTracker) incrSession(ctx context.Context, u types.TokenUsage, turns int64) error {
+	key := []string{usageSessionKeyPrefix + u.ChatId}
+	ttl := int64(usageSessionTTL / time.Second)
+	ok, err := usageIncrScript.Run(ctx, t.rdb, key,
+		u.PromptTokens, u.CompletionTokens, u.TotalTokens, turns, ttl).Int()
+	if err != nil {
+		return fmt.Errorf("redis incr session usage failed: %w", err)
+	}
+	if ok == 1 {
+		return nil
+	}
+
+	summary, err := t.dbSessionUsage(u.ChatId)
+	if err != nil {
+		return err
+	}
+	err = usageSeedScript.Run(ctx, t.rdb, key,
+		summary.PromptTokens, summary.CompletionTokens, summary.TotalTokens, summary.Turns, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("redis seed session usage failed: %w", err)
+	}
+	return nil
+}
+
+// SessionUsage 会话累计用量，Redis汇总过期时从数据库重新统计
+func (t *UsageTracker) SessionUsage(chatId string) (types.UsageSummary, error) {
+	summary, err := t.summary(usageSessionKeyPrefix + chatId)
+	if !errors.Is(err, redis.Nil) {
+		return summary, err
+	}
+	return t.dbSessionUsage(chatId)
+}
+
+// dbSessionUsage 从数据库统计会话累计用量
+func (t *UsageTracker) dbSessionUsage(chatId string) (types.UsageSummary, error) {
+	var summary types.UsageSummary
+	sql := `SELECT COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0), COALESCE(SUM(total_tokens), 0),
+		COUNT(*) FILTER (WHERE state <> $2)
+		FROM token_usage WHERE chat_id = $1`
+	err := t.pool.QueryRow(context.Background(), sql, chatId, types.TaskStateEval).
+		Scan(&summary.PromptTokens, &summary.CompletionTokens, &summary.TotalTokens, &summary.Turns)
+	if err != nil {
+		return summary, fmt.Errorf("DB select session usage: %w", err)
+	}
+	return summary, nil
+}
+
+// SessionTurns 会话每轮用量明细
+func (t *UsageTracker) SessionTurns(chatId string) ([]types.TokenUsage, error) {
+	sql := `SELECT chat_id, turn, state, provider, model, prompt_tokens, completion_tokens, total_tokens, estimated, created_at
+		FROM token_usage WHERE chat_id = $1 ORDER BY created_at`
+	rows, err := t.pool.Query(context.Background(), sql, chatId)
+	if err != nil {
+		return nil, fmt.Errorf("DB select session turns: %w", err)
+	}
+	defer rows.Close()
+
+	var turns []types.TokenUsage
+	for rows.Next() {
+		var u types.TokenUsage
+		if err := rows.Scan(&u.ChatId, &u.Turn, &u.State, &u.Provider, &u.Model,
+			&u.PromptTokens, &u.CompletionTokens, &u.TotalTokens, &u.Estimated, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan token usage: %w", err)
+		}
+		turns = append(turns, u)
+	}
+	return turns, rows.Err()
+}
+
+// DailyUsage 指定日期（yyyy-mm-dd，服务器时区）的用量汇总及会话数
+func (t *UsageTracker) DailyUsage(date string) (types.UsageSummary, int64, error) {
+	day, err := time.ParseInLocation(usageDateLayout, date, time.Local)
+	if err != nil {
+		return types.UsageSummary{}, 0, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+
+	summary, err := t.summary(usageDayKeyPrefix + date)
+	if err == nil {
+		sessions, err := t.rdb.PFCount(context.Background(), usageDaySessionsPrefix+date).Result()
+		return summary, sessions, err
+	}
+	if !errors.Is(err, redis.Nil) {
+		return summary, 0, err
+	}
+
+	var sessions int64
+	sql := `SELECT COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0), COALESCE(SUM(total_tokens), 0),
+		COUNT(*) FILTER (WHERE state <> $3), COUNT(DISTINCT chat_id)
+		FROM token_usage WHERE created_at >= $1 AND created_at < $2`
+	err = t.pool.QueryRow(context.Background(), sql, day, day.AddDate(0, 0, 1), types.TaskStateEval).
+		Scan(&summary.PromptTokens, &summary.CompletionTokens, &summary.TotalTokens, &summary.Turns, &sessions)
+	if err != nil {
+		return summary, 0, fmt.Errorf("DB select daily usage: %w", err)
+	}
+	return summary, sessions, nil
+}
+
+// summary 读取Redis汇总，不存在时返回redis.Nil
+func (t *UsageTracker) summary(key string) (types.UsageSummary, error) {
+	var summary types.UsageSummary
+	values, err := t.rdb.HMGet(context.Background(), key, "prompt", "completion", "total", "turns").Result()
+	if err != nil {
+		return summary, fmt.Errorf("redis hmget usage failed: %w", err)
+	}
+	if values[3] == nil {
+		return summary, redis.Nil
+	}
+
+	fields := []*int64{&summary.PromptTokens, &summary.CompletionTokens, &summary.TotalTokens, &summary.Turns}
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			*fields[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+	return summary, nil
+}
@@ -4,12 +4,15 @@ import (
 	"ai-gozero-agent/api/internal/config"
 	"ai-gozero-agent/api/internal/types"
 	"ai-gozero-agent/api/internal/utils"
+	"ai-gozero-agent/mcp/knowledgefilter"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sashabaranov/go-openai"
+	"github.com/zeromicro/go-zero/core/logx"
 	"time"
 )
 
@@ -92,34 +95,137 @@ func (vs *VectorStore) GetMessages(chatId string, limit int) ([]types.VectorMess
 	return messages, nil
 }
 
-func (vs *VectorStore) SaveKnowledge(title, content string, cfg config.VectorDBConfig) error {
-	fmt.Println("进入保存处理！！：", cfg.Knowledge.MaxChunkSize)
-	// 分块处理知识内容 todo
-	chunks := utils.SplitText(content, cfg.Knowledge.MaxChunkSize)
-	fmt.Println("分块处理内容！！：")
-	for _, chunk := range chunks {
-		fmt.Println("循环插入中！！：")
+// LastUserMessage 获取会话最近一条用户消息，没有时返回空字符串
+func (vs *VectorStore) LastUserMessage(chatId string) (string, error) {
+	sql := `SELECT content FROM vector_store WHERE chat_id = $1 AND role = 'user' ORDER BY created_at DESC, id DESC LIMIT 1`
+	var content string
+	err := vs.Pool.QueryRow(context.Background(), sql, chatId).Scan(&content)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("DB select LastUserMessage: %w", err)
+	}
+	return content, nil
+}
+
+// DeleteLastAssistantMessage 删除会话最近一条AI回复（仅当它是会话的最后一条消息时），返回删除的条数
+func (vs *VectorStore) DeleteLastAssistantMessage(chatId string) (int64, error) {
+	sql := `DELETE FROM vector_store WHERE id = (
+		SELECT id FROM vector_store WHERE chat_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1
+	) AND role = 'assistant'`
+	tag, err := vs.Pool.Exec(context.Background(), sql, chatId)
+	if err != nil {
+		return 0, fmt.Errorf("DB delete LastAssistantMessage: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// SaveKnowledge 分块保存知识，meta 为知识块的集合、主题、难度等元数据
+func (vs *VectorStore) SaveKnowledge(title, content string, meta KnowledgeMeta, cfg config.VectorDBConfig) error {
+	for _, chunk := range utils.SplitText(content, cfg.Knowledge.MaxChunkSize) {
+		if err := vs.SaveKnowledgeChunk(title, chunk, meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveKnowledgeChunk 保存一个已分块的知识块，不再拆分
+func (vs *VectorStore) SaveKnowledgeChunk(title, chunk string, meta KnowledgeMeta) error {
+	embedding, err := vs.generateEmbedding(chunk)
+	if err != nil {
+		return fmt.Errorf("generateEmbedding error: %w", err)
+	}
+
+	embeddingJson, err := json.Marshal(embedding)
+	if err != nil {
+		return fmt.Errorf("marshal embedding: %w", err)
+	}
+
+	tags := meta.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	sql := `INSERT INTO knowledge_base (title, content, embedding, collection, topic, difficulty, language, source, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = vs.Pool.Exec(context.Background(), sql, title, chunk, embeddingJson,
+		meta.Collection, meta.Topic, meta.Difficulty, meta.Language, meta.Source, tags)
+	if err != nil {
+		return fmt.Errorf("DB Insert Knowledge: %w", err)
+	}
+	return nil
+}
+
+// RetrieveKnowledge 在满足过滤条件的知识块中按向量相似度检索，filter 为nil时检索全部知识
+func (vs *VectorStore) RetrieveKnowledge(query string, topK int, filter *knowledgefilter.Filter) ([]types.KnowledgeChunk, error) {
+	queryEmbedding, err := vs.generateEmbedding(query)
+	if err != nil {
+		return nil, fmt.Errorf("generateEmbedding: %w", err)
+	}
+
+	queryEmbeddingJson, err := json.Marshal(queryEmbedding)
+	if err != nil {
+		return nil, fmt.Errorf("marshal embedding: %w", err)
+	}
+
+	// 使用余弦相似度检索
+	where, args := filter.Where(3)
+	sql := `SELECT id, title, content, collection, topic, difficulty, language, source, tags, created_at FROM knowledge_base
+		WHERE ` + where + ` ORDER BY embedding::jsonb::text <-> $1::text LIMIT $2`
+	rows, err := vs.Pool.Query(context.Background(), sql, append([]any{queryEmbeddingJson, topK}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("DB Select Knowledge: %w", err)
+	}
+	defer rows.Close()
+
+	var results []types.KnowledgeChunk
+	for rows.Next() {
+		c := types.KnowledgeChunk{Scope: types.KnowledgeScopeGlobal}
+		if err := rows.Scan(&c.ID, &c.Title, &c.Content, &c.Collection, &c.Topic, &c.Difficulty, &c.Language, &c.Source, &c.Tags, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("DB Select Knowledge: %w", err)
+		}
+		results = append(results, c)
+	}
+	return results, nil
+}
+
+// SaveSessionKnowledge 将会话附件分块存入会话级临时知识库，返回分块数量
+func (vs *VectorStore) SaveSessionKnowledge(chatId, title, content string, maxChunkSize int) (int, error) {
+	// 先生成全部向量，再在一个事务中写入，失败时不留下部分分块
+	chunks := utils.SplitText(content, maxChunkSize)
+	embeddings := make([][]byte, len(chunks))
+	for i, chunk := range chunks {
 		embedding, err := vs.generateEmbedding(chunk)
 		if err != nil {
-			return fmt.Errorf("generateEmbedding error: %w", err)
+			return 0, fmt.Errorf("generateEmbedding error: %w", err)
 		}
 
-		embeddingJson, err := json.Marshal(embedding)
-		if err != nil {
-			return fmt.Errorf("marshal embedding: %w", err)
+		if embeddings[i], err = json.Marshal(embedding); err != nil {
+			return 0, fmt.Errorf("marshal embedding: %w", err)
 		}
+	}
 
-		sql := `INSERT INTO knowledge_base (title, content, embedding) VALUES ($1, $2, $3)`
-		_, err = vs.Pool.Exec(context.Background(), sql, title, chunk, embeddingJson)
-		if err != nil {
-			return fmt.Errorf("DB Insert Knowledge: %w", err)
+	ctx := context.Background()
+	tx, err := vs.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("DB begin SessionKnowledge: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	sql := `INSERT INTO session_knowledge (chat_id, title, content, embedding) VALUES ($1, $2, $3, $4)`
+	for i, chunk := range chunks {
+		if _, err := tx.Exec(ctx, sql, chatId, title, chunk, embeddings[i]); err != nil {
+			return 0, fmt.Errorf("DB Insert SessionKnowledge: %w", err)
 		}
-		fmt.Println("插入成功！！：")
 	}
-	return nil
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("DB commit SessionKnowledge: %w", err)
+	}
+	return len(chunks), nil
 }
 
-func (vs *VectorStore) RetrieveKnowledge(query string, topK int) ([]types.KnowledgeChunk, error) {
+// RetrieveSessionKnowledge 检索指定会话的附件知识
+func (vs *VectorStore) RetrieveSessionKnowledge(chatId, query string, topK int) ([]types.KnowledgeChunk, error) {
 	queryEmbedding, err := vs.generateEmbedding(query)
 	if err != nil {
 		return nil, fmt.Errorf("generateEmbedding: %w", err)
@@ -130,11 +236,10 @@ func (vs *VectorStore) RetrieveKnowledge(query string, topK int) ([]types.Knowle
 		return nil, fmt.Errorf("marshal embedding: %w", err)
 	}
 
-	// 使用余弦相似度检索
-	sql := `SELECT id, title, content FROM knowledge_base ORDER BY embedding::jsonb::text <-> $1::text LIMIT $2`
-	rows, err := vs.Pool.Query(context.Background(), sql, queryEmbeddingJson, topK)
+	sql := `SELECT id, title, content FROM session_knowledge WHERE chat_id = $1 ORDER BY embedding::jsonb::text <-> $2::text LIMIT $3`
+	rows, err := vs.Pool.Query(context.Background(), sql, chatId, queryEmbeddingJson, topK)
 	if err != nil {
-		return nil, fmt.Errorf("DB Select Knowledge: %w", err)
+		return nil, fmt.Errorf("DB Select SessionKnowledge: %w", err)
 	}
 	defer rows.Close()
 
@@ -143,17 +248,55 @@ func (vs *VectorStore) RetrieveKnowledge(query string, topK int) ([]types.Knowle
 		var id int64
 		var title, content string
 		if err := rows.Scan(&id, &title, &content); err != nil {
-			return nil, fmt.Errorf("DB Select Knowledge: %w", err)
+			return nil, fmt.Errorf("DB Select SessionKnowledge: %w", err)
 		}
 		results = append(results, types.KnowledgeChunk{
 			ID:      id,
 			Title:   title,
 			Content: content,
+			Scope:   types.KnowledgeScopeSession,
 		})
 	}
 	return results, nil
 }
 
+// DeleteSessionKnowledge 清理指定会话的附件知识（面试结束时调用）
+func (vs *VectorStore) DeleteSessionKnowledge(chatId string) error {
+	sql := `DELETE FROM session_knowledge WHERE chat_id = $1`
+	if _, err := vs.Pool.Exec(context.Background(), sql, chatId); err != nil {
+		return fmt.Errorf("DB Delete SessionKnowledge: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredSessionKnowledge 清理超过保留时长的附件知识（会话未正常结束的兜底）
+func (vs *VectorStore) DeleteExpiredSessionKnowledge(maxAge time.Duration) (int64, error) {
+	sql := `DELETE FROM session_knowledge WHERE created_at < $1`
+	tag, err := vs.Pool.Exec(context.Background(), sql, time.Now().Add(-maxAge))
+	if err != nil {
+		return 0, fmt.Errorf("DB Delete expired SessionKnowledge: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// StartSessionKnowledgeJanitor 定期清理过期的会话附件知识
+func (vs *VectorStore) StartSessionKnowledgeJanitor(maxAge time.Duration) {
+	if maxAge <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if n, err := vs.DeleteExpiredSessionKnowledge(maxAge); err != nil {
+				logx.Errorf("clean expired session knowledge failed: %v", err)
+			} else if n > 0 {
+				logx.Infof("cleaned %d expired session knowledge chunks", n)
+			}
+		}
+	}()
+}
+
 // 生成向量文本
 func (vs *VectorStore) generateEmbedding(text string) ([]float32, error) {
 	if text == "" {
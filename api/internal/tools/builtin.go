@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"ai-gozero-agent/api/internal/svc"
+	"ai-gozero-agent/api/internal/types"
+	"ai-gozero-agent/api/internal/utils"
+	"ai-gozero-agent/api/sse"
+	"ai-gozero-agent/mcp/knowledgefilter"
+)
+
+// 内置工具名
+const (
+	NameSearchKnowledge = "search_knowledge"
+	NameGetNextQuestion = "get_next_question"
+	NameRecordScore     = "record_score"
+	NameSetState        = "set_state"
+	NameRunGoSnippet    = "run_go_snippet"
+)
+
+// NewDefaultRegistry 注册内置工具，allow 非空时只注册其中列出的工具；
+// runner 为空时不注册 run_go_snippet
+func NewDefaultRegistry(svcCtx *svc.ServiceContext, runner CodeRunner, allow []string) *Registry {
+	r := NewRegistry()
+	candidates := []Tool{
+		&searchKnowledgeTool{svcCtx: svcCtx},
+		&getNextQuestionTool{svcCtx: svcCtx},
+		&recordScoreTool{svcCtx: svcCtx},
+		&setStateTool{},
+	}
+	if runner != nil {
+		candidates = append(candidates, &runGoSnippetTool{runner: runner})
+	}
+	for _, t := range candidates {
+		if len(allow) == 0 || slices.Contains(allow, t.Name()) {
+			r.Register(t)
+		}
+	}
+	return r
+}
+
+// searchKnowledgeTool 按需检索知识库与候选人附件
+type searchKnowledgeTool struct {
+	svcCtx *svc.ServiceContext
+}
+
+func (t *searchKnowledgeTool) Name() string { return NameSearchKnowledge }
+
+func (t *searchKnowledgeTool) Description() string {
+	return "检索Go语言知识库和候选人上传的附件（简历、设计文档），用于出题、核对候选人回答或追问细节；可按主题、难度等元数据过滤知识库"
+}
+
+func (t *searchKnowledgeTool) Parameters() any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{"type": "string", "description": "检索内容"},
+			"topK":  map[string]any{"type": "integer", "description": "返回片段数量，默认3", "minimum": 1, "maximum": 10},
+			"filter": map[string]any{
+				"type":        "string",
+				"description": "知识库元数据过滤，逗号分隔的条件为“且”、| 分隔的取值为“或”，如 topic=concurrency, difficulty>=middle, tags=channel|select；可用字段 topic、difficulty（junior/middle/senior）、tags、language、source、title、collection、created_at",
+			},
+		},
+		"required": []string{"query"},
+	}
+}
+
+func (t *searchKnowledgeTool) Call(ctx context.Context, s *Session, args json.RawMessage) (any, error) {
+	var in struct {
+		Query  string `json:"query"`
+		TopK   int    `json:"topK"`
+		Filter string `json:"filter"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if in.Query == "" {
+		return nil, errors.New("query is required")
+	}
+	if in.TopK <= 0 || in.TopK > 10 {
+		in.TopK = 3
+	}
+
+	filter, err := knowledgefilter.Parse(in.Filter)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := t.svcCtx.VectorStore.RetrieveKnowledge(in.Query, in.TopK, s.Filter.And(filter))
+	if err != nil {
+		return nil, err
+	}
+	sessionChunks, err := t.svcCtx.VectorStore.RetrieveSessionKnowledge(s.ChatId, in.Query, in.TopK)
+	if err != nil {
+		return nil, err
+	}
+	chunks = append(chunks, sessionChunks...)
+
+	type result struct {
+		Title      string `json:"title"`
+		Scope      string `json:"scope"`
+		Topic      string `json:"topic,omitempty"`
+		Difficulty string `json:"difficulty,omitempty"`
+		Content    string `json:"content"`
+	}
+	maxLen := t.svcCtx.Config.VectorDB.Knowledge.MaxContextLength
+	results := make([]result, 0, len(chunks))
+	for _, c := range chunks {
+		results = append(results, result{Title: c.Title, Scope: c.Scope, Topic: c.Topic, Difficulty: c.Difficulty, Content: utils.TruncateText(c.Content, maxLen)})
+	}
+	return map[string]any{"results": results}, nil
+}
+
+// getNextQuestionTool 从题库抽取本场未出过的题目
+type getNextQuestionTool struct {
+	svcCtx *svc.ServiceContext
+}
+
+func (t *getNextQuestionTool) Name() string { return NameGetNextQuestion }
+
+func (t *getNextQuestionTool) Description() string {
+	return "从题库中抽取一道本场面试尚未出过的题目，附带参考答案要点（参考答案仅供评估，不要直接告诉候选人）"
+}
+
+func (t *getNextQuestionTool) Parameters() any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"topic":      map[string]any{"type": "string", "description": "题目主题，如 concurrency、memory、gc、basics，为空不限"},
+			"difficulty": map[string]any{"type": "string", "enum": []string{"junior", "middle", "senior"}, "description": "难度，为空不限"},
+		},
+	}
+}
+
+func (t *getNextQuestionTool) Call(ctx context.Context, s *Session, args json.RawMessage) (any, error) {
+	var in struct {
+		Topic      string `json:"topic"`
+		Difficulty string `json:"difficulty"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+
+	q, err := t.svcCtx.Interview.NextQuestion(s.ChatId, in.Topic, in.Difficulty)
+	if errors.Is(err, svc.ErrNoQuestion) {
+		return map[string]any{"found": false, "message": "题库中没有符合条件且未出过的题目"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"found": true, "question": q}, nil
+}
+
+// recordScoreTool 记录候选人某一维度的评分
+type recordScoreTool struct {
+	svcCtx *svc.ServiceContext
+}
+
+func (t *recordScoreTool) Name() string { return NameRecordScore }
+
+func (t *recordScoreTool) Description() string {
+	return "记录候选人在某一维度上的评分，例如并发编程、内存管理、工程实践、表达能力"
+}
+
+func (t *recordScoreTool) Parameters() any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"dimension": map[string]any{"type": "string", "description": "评分维度"},
+			"score":     map[string]any{"type": "number", "description": "得分"},
+			"maxScore":  map[string]any{"type": "number", "description": "满分，默认10"},
+			"comment":   map[string]any{"type": "string", "description": "评语"},
+		},
+		"required": []string{"dimension", "score"},
+	}
+}
+
+func (t *recordScoreTool) Call(ctx context.Context, s *Session, args json.RawMessage) (any, error) {
+	var in sse.ScoreData
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if in.Dimension == "" {
+		return nil, errors.New("dimension is required")
+	}
+	if in.MaxScore <= 0 {
+		in.MaxScore = 10
+	}
+	if in.Score < 0 || in.Score > in.MaxScore {
+		return nil, fmt.Errorf("score must be between 0 and %g", in.MaxScore)
+	}
+
+	if err := t.svcCtx.Interview.RecordScore(types.InterviewScore{
+		ChatId:    s.ChatId,
+		Dimension: in.Dimension,
+		Score:     in.Score,
+		MaxScore:  in.MaxScore,
+		Comment:   in.Comment,
+	}); err != nil {
+		return nil, err
+	}
+	s.Emit(sse.Score(in))
+	return map[string]any{"recorded": true}, nil
+}
+
+// setStateTool 由模型显式切换面试状态
+type setStateTool struct{}
+
+var interviewStates = []string{types.StateStart, types.StateQuestion, types.StateFollowUp, types.StateEvaluate, types.StateEnd}
+
+func (t *setStateTool) Name() string { return NameSetState }
+
+func (t *setStateTool) Description() string {
+	return "切换面试流程状态：question=提出新问题，follow_up=追问，evaluate=评估总结，end=结束面试"
+}
+
+func (t *setStateTool) Parameters() any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"state": map[string]any{"type": "string", "enum": interviewStates[1:]},
+		},
+		"required": []string{"state"},
+	}
+}
+
+func (t *setStateTool) Call(ctx context.Context, s *Session, args json.RawMessage) (any, error) {
+	var in struct {
+		State string `json:"state"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(interviewStates, in.State) {
+		return nil, fmt.Errorf("unknown state %q", in.State)
+	}
+	if err := s.SetState(in.State); err != nil {
+		return nil, err
+	}
+	return map[string]any{"state": in.State}, nil
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// CodeRunner 在隔离环境中运行Go代码
+type CodeRunner interface {
+	RunGo(ctx context.Context, req RunRequest) (*RunResult, error)
+}
+
+type RunRequest struct {
+	Code     string // main包源码，或被测试的包源码
+	TestCode string // 可选，_test.go 源码，非空时执行 go test
+	Vet      bool   // 是否执行 go vet
+}
+
+type TestResult struct {
+	Name    string  `json:"name"`
+	Passed  bool    `json:"passed"`
+	Elapsed float64 `json:"elapsed"` // 秒
+	Output  string  `json:"output,omitempty"`
+}
+
+type RunResult struct {
+	ExitCode int          `json:"exitCode"`
+	Stdout   string       `json:"stdout"`
+	Stderr   string       `json:"stderr"`
+	VetOut   string       `json:"vet,omitempty"`
+	Tests    []TestResult `json:"tests,omitempty"`
+	TimedOut bool         `json:"timedOut"`
+
+	BuildFailed bool `json:"buildFailed,omitempty"` // 编译失败时 Stderr 为编译错误
+}
+
+// runGoSnippetTool 运行候选人提交的Go代码
+type runGoSnippetTool struct {
+	runner CodeRunner
+}
+
+func (t *runGoSnippetTool) Name() string { return NameRunGoSnippet }
+
+func (t *runGoSnippetTool) Description() string {
+	return "在隔离环境中编译运行候选人的Go代码（无网络、限制时间与内存），可附带测试代码并执行go vet，返回输出与测试结果"
+}
+
+func (t *runGoSnippetTool) Parameters() any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"code":     map[string]any{"type": "string", "description": "Go源码，未提供测试时需为package main"},
+			"testCode": map[string]any{"type": "string", "description": "可选，_test.go 测试源码"},
+			"vet":      map[string]any{"type": "boolean", "description": "是否执行go vet"},
+		},
+		"required": []string{"code"},
+	}
+}
+
+func (t *runGoSnippetTool) Call(ctx context.Context, s *Session, args json.RawMessage) (any, error) {
+	var in struct {
+		Code     string `json:"code"`
+		TestCode string `json:"testCode"`
+		Vet      bool   `json:"vet"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if in.Code == "" {
+		return nil, errors.New("code is required")
+	}
+	return t.runner.RunGo(ctx, RunRequest{Code: in.Code, TestCode: in.TestCode, Vet: in.Vet})
+}
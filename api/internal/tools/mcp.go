@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"ai-gozero-agent/api/internal/mcpclient"
+)
+
+// RegisterMCP 注册外部MCP服务的工具
+func RegisterMCP(r *Registry, remote []*mcpclient.Tool) {
+	for _, t := range remote {
+		r.Register(&mcpTool{tool: t})
+	}
+}
+
+// mcpTool 代理外部MCP服务的工具
+type mcpTool struct {
+	tool *mcpclient.Tool
+}
+
+func (t *mcpTool) Name() string { return t.tool.QualifiedName }
+
+func (t *mcpTool) Description() string {
+	return "[" + t.tool.Server + "] " + t.tool.Description
+}
+
+func (t *mcpTool) Parameters() any { return t.tool.InputSchema }
+
+func (t *mcpTool) Call(ctx context.Context, s *Session, args json.RawMessage) (any, error) {
+	result, err := t.tool.Call(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return nil, errors.New(result.Text())
+	}
+
+	out := map[string]any{"content": result.Text()}
+	if len(result.StructuredContent) > 0 {
+		out["structuredContent"] = result.StructuredContent
+	}
+	return out, nil
+}
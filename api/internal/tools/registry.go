@@ -0,0 +1,107 @@
+// Package tools 面试官智能体可调用的工具：以JSON Schema声明，随对话请求下发给模型，
+// 模型发起调用后由 Registry 执行并将结果回填到对话中
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"ai-gozero-agent/api/sse"
+	"ai-gozero-agent/mcp/knowledgefilter"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Tool 单个工具
+type Tool interface {
+	Name() string
+	Description() string
+	Parameters() any // JSON Schema
+	Call(ctx context.Context, s *Session, args json.RawMessage) (any, error)
+}
+
+// Session 工具调用所在的会话上下文
+type Session struct {
+	ChatId   string
+	State    string                   // 当前面试状态
+	Filter   *knowledgefilter.Filter  // 本轮知识检索范围（集合与按状态配置的过滤条件），nil 表示不限
+	Emit     func(e *sse.Event)       // 向客户端推送事件
+	SetState func(state string) error // 切换面试状态
+}
+
+// Registry 工具注册表，按注册顺序下发
+type Registry struct {
+	tools map[string]Tool
+	order []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{tools: make(map[string]Tool)}
+}
+
+// Register 注册工具，同名工具覆盖
+func (r *Registry) Register(t Tool) {
+	if _, ok := r.tools[t.Name()]; !ok {
+		r.order = append(r.order, t.Name())
+	}
+	r.tools[t.Name()] = t
+}
+
+// Len 已注册工具数量
+func (r *Registry) Len() int {
+	return len(r.order)
+}
+
+// Definitions 转换为对话请求的 tools 参数
+func (r *Registry) Definitions() []openai.Tool {
+	defs := make([]openai.Tool, 0, len(r.order))
+	for _, name := range r.order {
+		t := r.tools[name]
+		defs = append(defs, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name(),
+				Description: t.Description(),
+				Parameters:  t.Parameters(),
+			},
+		})
+	}
+	return defs
+}
+
+// Execute 执行一次工具调用，返回回填给模型的JSON文本；执行失败时以 {"error": "..."} 告知模型
+func (r *Registry) Execute(ctx context.Context, s *Session, call openai.ToolCall) string {
+	t, ok := r.tools[call.Function.Name]
+	if !ok {
+		return errorResult(fmt.Errorf("unknown tool %q", call.Function.Name))
+	}
+
+	args := json.RawMessage(call.Function.Arguments)
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	result, err := t.Call(ctx, s, args)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errorResult(err)
+	}
+	return string(data)
+}
+
+func errorResult(err error) string {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(data)
+}
+
+// decodeArgs 解析工具参数
+func decodeArgs(args json.RawMessage, v any) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"ai-gozero-agent/sandbox/coderunner"
+)
+
+// sandboxRunner 通过 sandbox gRPC 服务运行代码
+type sandboxRunner struct {
+	client coderunner.CodeRunner
+}
+
+// NewSandboxRunner client 为nil时返回nil，即不注册 run_go_snippet 工具
+func NewSandboxRunner(client coderunner.CodeRunner) CodeRunner {
+	if client == nil {
+		return nil
+	}
+	return &sandboxRunner{client: client}
+}
+
+func (r *sandboxRunner) RunGo(ctx context.Context, req RunRequest) (*RunResult, error) {
+	resp, err := r.client.RunGo(ctx, &coderunner.RunGoRequest{
+		Code:     req.Code,
+		TestCode: req.TestCode,
+		Vet:      req.Vet,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+
+	result := &RunResult{
+		ExitCode: int(resp.ExitCode),
+		Stdout:   resp.Stdout,
+		Stderr:   resp.Stderr,
+		VetOut:   resp.VetOutput,
+		TimedOut: resp.TimedOut,
+
+		BuildFailed: resp.BuildFailed,
+	}
+	for _, t := range resp.Tests {
+		result.Tests = append(result.Tests, TestResult{Name: t.Name, Passed: t.Passed, Elapsed: t.Elapsed, Output: t.Output})
+	}
+	return result, nil
+}
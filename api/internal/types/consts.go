@@ -7,3 +7,13 @@ const (
 	StateEvaluate = "evaluate"  // 评估总结阶段
 	StateEnd      = "end"       // 面试结束
 )
+
+const (
+	KnowledgeScopeGlobal  = "global"  // 全局知识库
+	KnowledgeScopeSession = "session" // 会话附件知识库
+)
+
+// 模型任务，与面试状态一起作为 Profiles 的键；总结与评分在 evaluate/end 状态的对话中完成，没有单独的任务
+const (
+	TaskStateEval = "state_eval" // 状态转移评估（配置后由模型判断下一状态）
+)
@@ -1,6 +1,10 @@
 package types
 
-import "github.com/sashabaranov/go-openai"
+import (
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
 
 type ChatSession struct {
 	Messages []openai.ChatCompletionMessage `json:"message"` // 存储对话历史（系统消息+用户消息+AI回复）
@@ -15,6 +19,16 @@ type KnowledgeChunk struct {
 	ID      int64  `json:"id"`      // 知识块ID
 	Title   string `json:"title"`   // 知识标题
 	Content string `json:"content"` // 知识内容
+	Scope   string `json:"scope"`   // 知识来源范围（global/session）
+
+	// 全局知识库的元数据，会话附件为空
+	Collection string    `json:"collection,omitempty"`
+	Topic      string    `json:"topic,omitempty"`
+	Difficulty string    `json:"difficulty,omitempty"`
+	Language   string    `json:"language,omitempty"`
+	Source     string    `json:"source,omitempty"` // 来源文档
+	Tags       []string  `json:"tags,omitempty"`
+	CreatedAt  time.Time `json:"createdAt,omitzero"`
 }
 
 type SessionStore interface {
@@ -29,3 +43,39 @@ type SessionStore interface {
 //	GetSession(chatId string) (*ChatSession, error)        // 根据 chatId 获取或创建会话
 //	SaveSession(chatId string, session *ChatSession) error // 保存更新后的会话
 //}
+
+type TokenUsage struct {
+	ChatId           string    `json:"chatId"`
+	Turn             int64     `json:"turn"`             // 生成轮次
+	State            string    `json:"state"`            // 生成时的面试状态，状态评估调用为 state_eval
+	Provider         string    `json:"provider"`         // 实际提供服务的提供方
+	Model            string    `json:"model"`            // 实际使用的模型
+	PromptTokens     int       `json:"promptTokens"`     // 输入token
+	CompletionTokens int       `json:"completionTokens"` // 输出token（含推理内容）
+	TotalTokens      int       `json:"totalTokens"`      // 合计
+	Estimated        bool      `json:"estimated"`        // 是否为估算值
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+type UsageSummary struct {
+	PromptTokens     int64 `json:"promptTokens"`
+	CompletionTokens int64 `json:"completionTokens"`
+	TotalTokens      int64 `json:"totalTokens"`
+	Turns            int64 `json:"turns"` // 生成轮数
+}
+
+type Question struct {
+	ID              int64  `json:"id"`
+	Topic           string `json:"topic"`           // 主题，如 concurrency、gc
+	Difficulty      string `json:"difficulty"`      // 难度：junior/middle/senior
+	Content         string `json:"content"`         // 题目
+	ReferenceAnswer string `json:"referenceAnswer"` // 参考答案要点
+}
+
+type InterviewScore struct {
+	ChatId    string  `json:"chatId"`
+	Dimension string  `json:"dimension"` // 评分维度
+	Score     float64 `json:"score"`
+	MaxScore  float64 `json:"maxScore"`
+	Comment   string  `json:"comment"`
+}
@@ -3,22 +3,174 @@
 
 package types
 
+type ChatCancelReq struct {
+	ChatId      string `form:"chatId"`
+	KeepPartial bool   `form:"keepPartial,optional"` // 是否保留已生成的部分内容
+}
+
+type ChatCancelResp struct {
+	Cancelled bool `json:"cancelled"` // 是否存在并取消了进行中的生成
+}
+
+type ChatRegenerateReq struct {
+	ChatId string `form:"chatId"`
+}
+
 type ChatResponse struct {
 	Content string `json:"content"`
 	IsLast  bool   `json:"isLast"`
 }
 
+type ChatTicketReq struct {
+	ChatId string `form:"chatId"`
+}
+
+type ChatTicketResp struct {
+	Ticket    string `json:"ticket"`    // 以 ticket 参数连接SSE与WebSocket接口
+	ExpiresAt int64  `json:"expiresAt"` // unix秒
+}
+
+type ChatUsageReq struct {
+	ChatId string `form:"chatId"`
+}
+
+type ChatUsageResp struct {
+	ChatId           string      `json:"chatId"`
+	PromptTokens     int64       `json:"promptTokens"`
+	CompletionTokens int64       `json:"completionTokens"`
+	TotalTokens      int64       `json:"totalTokens"`
+	Budget           int64       `json:"budget"`    // 会话token预算，0为不限制
+	Remaining        int64       `json:"remaining"` // 剩余预算，不限制时为-1
+	Turns            []TurnUsage `json:"turns"`
+}
+
+type DailyUsageReq struct {
+	Date string `form:"date,optional"` // yyyy-mm-dd，默认当天
+}
+
+type DailyUsageResp struct {
+	Date             string `json:"date"`
+	PromptTokens     int64  `json:"promptTokens"`
+	CompletionTokens int64  `json:"completionTokens"`
+	TotalTokens      int64  `json:"totalTokens"`
+	Turns            int64  `json:"turns"`    // 生成轮数
+	Sessions         int64  `json:"sessions"` // 会话数
+}
+
 type InterViewAPPChatReq struct {
-	Message string `form:"message"`
-	ChatId  string `form:"chatId"`
+	Message     string `form:"message"`
+	ChatId      string `form:"chatId"`
+	Collections string `form:"collections,optional"` // 会话检索的知识库集合，逗号分隔，设置后整场面试有效
+}
+
+type InterViewAPPResumeReq struct {
+	ChatId string `form:"chatId"`
+}
+
+type InterViewAPPWsReq struct {
+	ChatId      string `form:"chatId"`
+	Collections string `form:"collections,optional"` // 会话检索的知识库集合，逗号分隔
+}
+
+type KnowledgeBatchReq struct {
+	BatchId string `form:"batchId"`
+}
+
+type KnowledgeCollection struct {
+	Name      string `json:"name"`
+	Documents int64  `json:"documents"` // 文档数（按标题去重）
+	Chunks    int64  `json:"chunks"`    // 知识块数
+}
+
+type KnowledgeCollectionsResp struct {
+	Collections []KnowledgeCollection `json:"collections"`
+}
+
+type KnowledgeExportReq struct {
+	Collections string `form:"collections,optional"` // 导出的集合，逗号分隔，为空时导出全部集合
+	Filter      string `form:"filter,optional"`      // 元数据过滤表达式，语法同知识检索
+	Embeddings  bool   `form:"embeddings,optional"`  // 是否导出向量，导入环境使用相同向量模型时可免去重新生成
+}
+
+type KnowledgeImportItem struct {
+	Path        string   `json:"path"` // 归档中的相对路径
+	Title       string   `json:"title"`
+	Collection  string   `json:"collection"`
+	Tags        []string `json:"tags"`
+	JobId       string   `json:"jobId"`       // 为空表示未能入队
+	Status      string   `json:"status"`      // rejected（未能入队）或任务状态
+	Error       string   `json:"error"`       // 未能入队或任务失败的原因
+	SavedChunks int      `json:"savedChunks"` // 已入库的知识块数
+}
+
+type KnowledgeImportReq struct {
+	Tags       string `form:"tags,optional"`       // 追加到所有文件的标签，逗号分隔
+	Collection string `form:"collection,optional"` // 清单未指定集合时写入的集合，默认 default
+	Topic      string `form:"topic,optional"`      // 清单未指定主题时的主题
+	Difficulty string `form:"difficulty,optional"` // 清单未指定难度时的难度：junior、middle、senior
+	Language   string `form:"language,optional"`   // 清单未指定语言时的语言，为空时按内容识别
+}
+
+type KnowledgeImportResp struct {
+	BatchId   string                `json:"batchId"` // 批次ID，通过批次接口查询汇总
+	Total     int                   `json:"total"`
+	Rejected  int                   `json:"rejected"` // 未能入队（格式不支持、超过大小限制、清单中的文件不存在等）
+	Queued    int                   `json:"queued"`
+	Running   int                   `json:"running"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Cancelled int                   `json:"cancelled"`
+	Done      bool                  `json:"done"` // 所有任务均已结束
+	Items     []KnowledgeImportItem `json:"items"`
+}
+
+type KnowledgeJobReq struct {
+	JobId string `form:"jobId"`
+}
+
+type KnowledgeJobResp struct {
+	JobId       string   `json:"jobId"`
+	Title       string   `json:"title"`
+	Filename    string   `json:"filename"`
+	Collection  string   `json:"collection"`
+	Topic       string   `json:"topic"`
+	Difficulty  string   `json:"difficulty"`
+	Language    string   `json:"language"` // 为空时入库时按内容识别
+	Tags        []string `json:"tags"`
+	Status      string   `json:"status"`      // queued、running、succeeded、failed、cancelled
+	Stage       string   `json:"stage"`       // extract（解析文档）或 embed（生成向量并入库）
+	Percent     float64  `json:"percent"`     // 总进度 0-100
+	Chunks      int      `json:"chunks"`      // 知识块总数
+	SavedChunks int      `json:"savedChunks"` // 已入库的知识块数
+	FailedPages int      `json:"failedPages"` // 解析失败被跳过的页数
+	Attempts    int      `json:"attempts"`
+	Error       string   `json:"error"`
+	CreatedAt   int64    `json:"createdAt"` // unix秒
+	UpdatedAt   int64    `json:"updatedAt"`
 }
 
 type KnowledgeUploadReq struct {
-	Title   string `form:"title"`   // 知识标题
-	Content string `form:"content"` // 知识内容
+	Title      string `form:"title,optional"`      // 知识标题，为空时使用文档标题或文件名
+	Tags       string `form:"tags,optional"`       // 标签，逗号分隔
+	Collection string `form:"collection,optional"` // 写入的知识库集合，默认 default
+	Topic      string `form:"topic,optional"`      // 主题，如 concurrency、gc
+	Difficulty string `form:"difficulty,optional"` // 难度：junior、middle、senior
+	Language   string `form:"language,optional"`   // 语言，为空时按内容识别
 }
 
 type KnowledgeUploadResp struct {
-	Msg    string `json:"msg"`
-	Chunks int    `json:"chunks"` // 保存知识块数量
+	Msg   string `json:"msg"`
+	JobId string `json:"jobId"` // 导入任务ID，通过任务接口查询进度
+}
+
+type TurnUsage struct {
+	Turn             int64  `json:"turn"`
+	State            string `json:"state"`
+	Provider         string `json:"provider"`
+	Model            string `json:"model"`
+	PromptTokens     int    `json:"promptTokens"`
+	CompletionTokens int    `json:"completionTokens"`
+	TotalTokens      int    `json:"totalTokens"`
+	Estimated        bool   `json:"estimated"` // 是否为估算值
+	CreatedAt        int64  `json:"createdAt"` // unix秒
 }
@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// CombineMessages 拼接用户消息和附件内容
+// 不超过 inlineLimit 时直接拼接全文；超过时全文已存入会话级知识库，只附加提示，由检索按需注入相关片段
+func CombineMessages(userMsg, filename, pdfContent string, inlineLimit int) string {
+	// 空内容直接返回用户消息
+	if pdfContent == "" {
+		return userMsg
+	}
+
+	// 检查附件内容长度
+	if length := len([]rune(pdfContent)); length > inlineLimit {
+		return fmt.Sprintf("%s\n[系统提示]已上传附件《%s》（约%d字），全文已存入本次面试的附件知识库，将按需检索相关片段", userMsg, filename, length)
+	}
+
+	return userMsg + "\n[附件内容开始]" + pdfContent + "[附件内容结束]"
+}
+
+// SplitText 将文本分割为不超过 maxChunkSize 字符的块：按标题、段落、代码块与表格边界切分，
+// 代码块与表格尽量保持完整，超长的块再按行、按字符切分；块不是从标题开始时以所属标题路径开头，检索时保留上下文
+func SplitText(text string, maxChunkSize int) []string {
+	var chunks []string
+	var sb strings.Builder
+	size := 0
+	flush := func() {
+		if size > 0 {
+			chunks = append(chunks, sb.String())
+			sb.Reset()
+			size = 0
+		}
+	}
+	write := func(s string, sep string) {
+		if size > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString(sep)
+			size += len([]rune(sep))
+		}
+		sb.WriteString(s)
+		size += len([]rune(s))
+	}
+
+	// 标题与其后的内容合并，避免标题单独留在上一块末尾；合并后仍超长时标题只体现在标题路径中
+	var pending []textBlock
+	add := func(b textBlock) {
+		if len(pending) > 0 {
+			texts := make([]string, 0, len(pending)+1)
+			for _, h := range pending {
+				texts = append(texts, h.text)
+			}
+			merged := textBlock{text: strings.Join(append(texts, b.text), "\n\n"), heading: true, path: pending[0].path}
+			if len([]rune(merged.text)) <= maxChunkSize {
+				b = merged
+			}
+			pending = nil
+		}
+
+		n := len([]rune(b.text))
+		// 章节较长时从新标题开始新块
+		if size > 0 && (size+2+n > maxChunkSize || b.heading && size > maxChunkSize/2) {
+			flush()
+		}
+		prefix := ""
+		if b.path != "" && len([]rune(b.path)) <= maxChunkSize/4 {
+			prefix = "[" + b.path + "]\n"
+		}
+		if size == 0 && prefix != "" {
+			write(prefix, "")
+		}
+		if size+2+n <= maxChunkSize {
+			write(b.text, "\n\n")
+			return
+		}
+
+		// 超长块：按行切分，单行仍超长时按字符切分；只有标题路径的块不单独输出
+		for _, piece := range splitLong(b.text, maxChunkSize-len([]rune(prefix))) {
+			if size > len([]rune(prefix)) && size+1+len([]rune(piece)) > maxChunkSize {
+				flush()
+				write(prefix, "")
+			}
+			write(piece, "\n")
+		}
+	}
+
+	for _, b := range splitBlocks(text) {
+		if b.heading {
+			pending = append(pending, b)
+			continue
+		}
+		add(b)
+	}
+	if len(pending) > 0 {
+		last := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		add(last)
+	}
+	flush()
+	return chunks
+}
+
+// textBlock 标题、段落、代码块或表格
+type textBlock struct {
+	text    string
+	heading bool
+	path    string // 所属标题路径，如 "Go并发 > channel"
+}
+
+var headingLine = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+func splitBlocks(text string) []textBlock {
+	var blocks []textBlock
+	var titles [6]string // 各级标题，titles[i] 为 i+1 级
+	var cur []string
+	fence := ""
+	inTable := false
+	path := func() string {
+		var parts []string
+		for _, t := range titles {
+			if t != "" {
+				parts = append(parts, t)
+			}
+		}
+		return strings.Join(parts, " > ")
+	}
+	flush := func() {
+		if len(cur) > 0 {
+			blocks = append(blocks, textBlock{text: strings.Join(cur, "\n"), path: path()})
+			cur = nil
+		}
+		inTable = false
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			cur = append(cur, line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				flush()
+			}
+			continue
+		}
+
+		switch m := headingLine.FindStringSubmatch(line); {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence = trimmed[:3]
+			cur = append(cur, line)
+		case m != nil:
+			flush()
+			level := len(m[1])
+			clear(titles[level-1:])
+			blocks = append(blocks, textBlock{text: line, heading: true, path: path()})
+			titles[level-1] = m[2]
+		case trimmed == "":
+			flush()
+		default:
+			// 表格与相邻段落分开
+			if isTable := strings.HasPrefix(trimmed, "|"); isTable != inTable {
+				flush()
+				inTable = isTable
+			}
+			cur = append(cur, line)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// splitLong 按行组合为不超过 maxLen 字符的片段，单行超长时按字符切分
+func splitLong(text string, maxLen int) []string {
+	maxLen = max(maxLen, 1)
+	var pieces []string
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		for len(runes) > maxLen {
+			pieces = append(pieces, string(runes[:maxLen]))
+			runes = runes[maxLen:]
+		}
+		pieces = append(pieces, string(runes))
+	}
+	return pieces
+}
+
+// TruncateText 截断文本到指定长度
+func TruncateText(text string, maxLen int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return string(runes[:maxLen]) + "..."
+}
+
+// DetectLanguage 粗略识别文本语言：汉字占比较高时为 zh，其余有拉丁字母时为 en，否则返回空字符串；
+// 一个汉字约相当于一个英文单词，按汉字数的4倍与字母数比较，代码片段较多的中文文档仍识别为 zh
+func DetectLanguage(text string) string {
+	han, latin := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			latin++
+		}
+	}
+	switch {
+	case han > 0 && han*4 >= latin:
+		return "zh"
+	case latin > 0:
+		return "en"
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrVersionMismatch 事件协议版本与客户端不一致
+var ErrVersionMismatch = errors.New("sse: protocol version mismatch")
+
+// Reader 从SSE流中逐个解析事件
+type Reader struct {
+	scanner *bufio.Scanner
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	return &Reader{scanner: scanner}
+}
+
+// Next 返回下一个事件，流结束时返回io.EOF
+func (r *Reader) Next() (*Event, error) {
+	var (
+		id, typ string
+		data    []string
+	)
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if len(data) == 0 {
+				continue
+			}
+			return parseFrame(id, typ, strings.Join(data, "\n"))
+		}
+		if strings.HasPrefix(line, ":") { // 注释/心跳
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "event":
+			typ = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		return parseFrame(id, typ, strings.Join(data, "\n"))
+	}
+	return nil, io.EOF
+}
+
+func parseFrame(id, typ, data string) (*Event, error) {
+	var env envelope
+	if err := json.Unmarshal([]byte(data), &env); err != nil {
+		return nil, fmt.Errorf("sse: decode event %s: %w", id, err)
+	}
+	if env.Version != ProtocolVersion {
+		return nil, fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, env.Version, ProtocolVersion)
+	}
+	if typ == "" {
+		typ = env.Type
+	}
+	return &Event{ID: id, Type: typ, Data: env.Data}, nil
+}
+
+// ParseFrame 解析WebSocket帧
+func ParseFrame(frame []byte) (*Event, error) {
+	var env envelope
+	if err := json.Unmarshal(frame, &env); err != nil {
+		return nil, fmt.Errorf("sse: decode frame: %w", err)
+	}
+	if env.Version != ProtocolVersion {
+		return nil, fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, env.Version, ProtocolVersion)
+	}
+	return &Event{ID: env.ID, Type: env.Type, Data: env.Data}, nil
+}
+
+// Client 聊天接口客户端，供集成测试和命令行工具使用
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// ChatRequest 聊天请求
+type ChatRequest struct {
+	ChatId   string
+	Message  string
+	FilePath string // 可选，随消息上传的附件（PDF、DOCX、EPUB、HTML、Markdown或文本）
+}
+
+// Stream 一次聊天请求的事件流
+type Stream struct {
+	*Reader
+	body io.ReadCloser
+}
+
+func (s *Stream) Close() error {
+	return s.body.Close()
+}
+
+// Chat 发起聊天请求并返回事件流
+func (c *Client) Chat(ctx context.Context, req ChatRequest) (*Stream, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.WriteField("chatId", req.ChatId); err != nil {
+		return nil, err
+	}
+	if err := mw.WriteField("message", req.Message); err != nil {
+		return nil, err
+	}
+	if req.FilePath != "" {
+		if err := attachFile(mw, req.FilePath); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/api/ai/interview_app/chat/sse", &body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("sse: unexpected status %s", resp.Status)
+	}
+	if v := resp.Header.Get(ProtocolHeader); v != "" && v != strconv.Itoa(ProtocolVersion) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: server speaks %s", ErrVersionMismatch, v)
+	}
+
+	return &Stream{Reader: NewReader(resp.Body), body: resp.Body}, nil
+}
+
+func attachFile(mw *multipart.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := make(map[string][]string)
+	h["Content-Disposition"] = []string{fmt.Sprintf(`form-data; name="file"; filename=%q`, filepath.Base(path))}
+	h["Content-Type"] = []string{"application/pdf"}
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, f)
+	return err
+}
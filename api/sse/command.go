@@ -0,0 +1,18 @@
+package sse
+
+// WebSocket 客户端指令类型
+const (
+	CommandMessage = "message" // 发送用户消息，开始新一轮生成
+	CommandCancel  = "cancel"  // 取消当前生成
+	CommandPing    = "ping"    // 心跳
+	CommandTyping  = "typing"  // 正在输入
+)
+
+// Command WebSocket 客户端发送的指令
+type Command struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"` // CommandMessage
+	Typing  bool   `json:"typing,omitempty"`  // CommandTyping
+
+	KeepPartial bool `json:"keepPartial,omitempty"` // CommandCancel 是否保留已生成的部分内容
+}
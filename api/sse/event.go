@@ -0,0 +1,194 @@
+// Package sse 定义聊天流使用的 SSE 事件协议，服务端写入与客户端解析共用同一套类型
+package sse
+
+import "encoding/json"
+
+// ProtocolVersion 事件协议版本，随每个事件下发，协议不兼容变更时递增
+const ProtocolVersion = 1
+
+// ProtocolHeader 响应头中声明协议版本
+const ProtocolHeader = "X-SSE-Protocol"
+
+// 事件类型
+const (
+	EventToken        = "token"         // 增量文本
+	EventReasoning    = "reasoning"     // 推理模型的思考内容（仅面试官可见）
+	EventStateChanged = "state_changed" // 面试状态变更
+	EventSources      = "sources"       // 本轮引用的知识来源
+	EventScore        = "score"         // 评分结果
+	EventUsage        = "usage"         // token用量
+	EventError        = "error"         // 错误
+	EventDone         = "done"          // 本轮结束
+	EventNotice       = "notice"        // 服务端主动推送的提示
+	EventPong         = "pong"          // 心跳响应（仅WebSocket）
+	EventProgress     = "progress"      // 附件上传与解析进度
+)
+
+// 结束原因
+const (
+	DoneReasonStop      = "stop"      // 正常结束
+	DoneReasonCancelled = "cancelled" // 被取消
+	DoneReasonError     = "error"     // 出错结束
+)
+
+// Event 单个SSE事件
+type Event struct {
+	ID   string // 事件ID，为空时由Writer自动分配
+	Type string // 事件类型
+	Data any    // 事件负载，写入时序列化为JSON，解析时为json.RawMessage
+}
+
+// envelope data 字段的JSON结构，WebSocket帧额外携带id
+type envelope struct {
+	Version int             `json:"v"`
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type TokenData struct {
+	Content string `json:"content"`
+}
+
+type ReasoningData struct {
+	Content string `json:"content"`
+}
+
+type StateChangedData struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Source struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Scope   string `json:"scope"`
+	Snippet string `json:"snippet,omitempty"`
+
+	Collection string `json:"collection,omitempty"` // 知识库集合，会话附件为空
+}
+
+type SourcesData struct {
+	Sources []Source `json:"sources"`
+}
+
+type ScoreData struct {
+	Dimension string  `json:"dimension"`
+	Score     float64 `json:"score"`
+	MaxScore  float64 `json:"maxScore"`
+	Comment   string  `json:"comment,omitempty"`
+}
+
+type UsageData struct {
+	PromptTokens     int  `json:"promptTokens"`
+	CompletionTokens int  `json:"completionTokens"`
+	TotalTokens      int  `json:"totalTokens"`
+	Estimated        bool `json:"estimated"` // 是否为估算值（服务端未返回用量时）
+}
+
+// 进度阶段
+const (
+	ProgressUpload  = "upload"  // 上传附件
+	ProgressExtract = "extract" // 逐页解析
+)
+
+type ProgressData struct {
+	Stage   string  `json:"stage"`
+	Percent float64 `json:"percent"`         // 0-100
+	Page    int     `json:"page,omitempty"`  // extract：当前页码
+	Pages   int     `json:"pages,omitempty"` // extract：总页数
+	Error   string  `json:"error,omitempty"` // extract：该页解析失败原因
+	// extract：该页为扫描件，文本由OCR识别及其平均置信度（0-1）
+	OCR        bool    `json:"ocr,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+type ErrorData struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type DoneData struct {
+	Reason string `json:"reason"`
+}
+
+type NoticeData struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// 服务端提示码
+const (
+	NoticeTimeUp = "time_up" // 面试时间已到
+	NoticeIdle   = "idle"    // 候选人长时间未作答
+
+	NoticeBudgetExhausted = "budget_exhausted" // 会话token预算已用尽
+)
+
+// 错误码
+const (
+	ErrCodeBadRequest = "bad_request"
+	ErrCodeHistory    = "history_failed"
+	ErrCodeUpstream   = "upstream_failed"
+	ErrCodeInternal   = "internal"
+	ErrCodeExpired    = "stream_expired" // 续传的事件流已过期
+	ErrCodeBusy       = "busy"           // 上一轮生成尚未结束
+)
+
+func Token(content string) *Event {
+	return &Event{Type: EventToken, Data: TokenData{Content: content}}
+}
+
+func Reasoning(content string) *Event {
+	return &Event{Type: EventReasoning, Data: ReasoningData{Content: content}}
+}
+
+func StateChanged(from, to string) *Event {
+	return &Event{Type: EventStateChanged, Data: StateChangedData{From: from, To: to}}
+}
+
+func Sources(sources []Source) *Event {
+	return &Event{Type: EventSources, Data: SourcesData{Sources: sources}}
+}
+
+func Score(data ScoreData) *Event {
+	return &Event{Type: EventScore, Data: data}
+}
+
+func Usage(data UsageData) *Event {
+	return &Event{Type: EventUsage, Data: data}
+}
+
+func Error(code, message string) *Event {
+	return &Event{Type: EventError, Data: ErrorData{Code: code, Message: message}}
+}
+
+func Done(reason string) *Event {
+	return &Event{Type: EventDone, Data: DoneData{Reason: reason}}
+}
+
+func Notice(code, message string) *Event {
+	return &Event{Type: EventNotice, Data: NoticeData{Code: code, Message: message}}
+}
+
+func Progress(data ProgressData) *Event {
+	return &Event{Type: EventProgress, Data: data}
+}
+
+func Pong() *Event {
+	return &Event{Type: EventPong, Data: struct{}{}}
+}
+
+// Decode 将解析得到的事件负载反序列化到v
+func (e *Event) Decode(v any) error {
+	switch data := e.Data.(type) {
+	case json.RawMessage:
+		return json.Unmarshal(data, v)
+	default:
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(raw, v)
+	}
+}
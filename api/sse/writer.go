@@ -0,0 +1,98 @@
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Writer 将事件按协议写入HTTP响应
+type Writer struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	lock    sync.Mutex
+	seq     int64
+}
+
+func NewWriter(w http.ResponseWriter) *Writer {
+	flusher, _ := w.(http.Flusher)
+	return &Writer{w: w, flusher: flusher}
+}
+
+// SetHeader 设置SSE响应头
+func (sw *Writer) SetHeader() {
+	h := sw.w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("Access-Control-Allow-Origin", "*")
+	h.Set("X-Accel-Buffering", "no")
+	h.Set(ProtocolHeader, strconv.Itoa(ProtocolVersion))
+}
+
+// Write 写入单个事件并立即刷新
+func (sw *Writer) Write(e *Event) error {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+
+	frame, err := sw.encode(e)
+	if err != nil {
+		return err
+	}
+	if _, err := sw.w.Write(frame); err != nil {
+		return err
+	}
+	if sw.flusher != nil {
+		sw.flusher.Flush()
+	}
+	return nil
+}
+
+// Error 写入错误事件，随后写入结束事件
+func (sw *Writer) Error(code, message string) error {
+	if err := sw.Write(Error(code, message)); err != nil {
+		return err
+	}
+	return sw.Write(Done(DoneReasonError))
+}
+
+func (sw *Writer) encode(e *Event) ([]byte, error) {
+	if e.ID == "" {
+		sw.seq++
+		e.ID = strconv.FormatInt(sw.seq, 10)
+	}
+	data, err := Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return []byte(b.String()), nil
+}
+
+// Marshal 序列化事件的data字段
+func Marshal(e *Event) ([]byte, error) {
+	return marshal(e, "")
+}
+
+// MarshalFrame 序列化为WebSocket帧，事件ID放在JSON内
+func MarshalFrame(e *Event) ([]byte, error) {
+	return marshal(e, e.ID)
+}
+
+func marshal(e *Event, id string) ([]byte, error) {
+	payload, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s event: %w", e.Type, err)
+	}
+	return json.Marshal(envelope{
+		Version: ProtocolVersion,
+		ID:      id,
+		Type:    e.Type,
+		Data:    payload,
+	})
+}
@@ -21,10 +21,96 @@ CREATE TABLE IF NOT EXISTS "public"."knowledge_base" (
      "title" VARCHAR(255) NOT NULL,
     "content" TEXT NOT NULL,
     "embedding" JSONB NOT NULL,
+    "collection" VARCHAR(64) NOT NULL DEFAULT 'default',
+    "topic" VARCHAR(100) NOT NULL DEFAULT '',
+    "difficulty" VARCHAR(20) NOT NULL DEFAULT '',
+    "language" VARCHAR(20) NOT NULL DEFAULT '',
+    "source" VARCHAR(1024) NOT NULL DEFAULT '',
+    "tags" TEXT[] NOT NULL DEFAULT '{}',
+    "content_hash" CHAR(32) GENERATED ALWAYS AS (md5(content)) STORED,
     "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
     );
+-- 已有数据库升级：知识块元数据（集合、主题、难度、语言、来源文档与标签）
+ALTER TABLE knowledge_base ADD COLUMN IF NOT EXISTS "tags" TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE knowledge_base ADD COLUMN IF NOT EXISTS "collection" VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE knowledge_base ADD COLUMN IF NOT EXISTS "topic" VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE knowledge_base ADD COLUMN IF NOT EXISTS "difficulty" VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE knowledge_base ADD COLUMN IF NOT EXISTS "language" VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE knowledge_base ADD COLUMN IF NOT EXISTS "source" VARCHAR(1024) NOT NULL DEFAULT '';
+-- 已有数据库升级：内容哈希（由数据库生成，已有数据自动回填），快照导入时按其判断知识块是否已存在
+ALTER TABLE knowledge_base ADD COLUMN IF NOT EXISTS "content_hash" CHAR(32) GENERATED ALWAYS AS (md5(content)) STORED;
+
+-- 创建会话附件知识表（会话级临时知识库，面试结束后清理）
+CREATE TABLE IF NOT EXISTS "public"."session_knowledge" (
+     "id" BIGSERIAL PRIMARY KEY,
+     "chat_id" VARCHAR(255) NOT NULL,
+     "title" TEXT NOT NULL,
+    "content" TEXT NOT NULL,
+    "embedding" JSONB NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    );
+-- 附件文件名长度不受限，已有库的标题列改为TEXT
+ALTER TABLE session_knowledge ALTER COLUMN "title" TYPE TEXT;
 
 -- 创建索引
 CREATE INDEX IF NOT EXISTS idx_vector_store_chat_id ON vector_store (chat_id);
 CREATE INDEX IF NOT EXISTS idx_vector_store_created_at ON vector_store (created_at DESC);
-CREATE INDEX IF NOT EXISTS idx_knowledge_base_title ON knowledge_base (title);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_knowledge_base_title ON knowledge_base (title);
+CREATE INDEX IF NOT EXISTS idx_knowledge_base_tags ON knowledge_base USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_knowledge_base_collection ON knowledge_base (collection);
+CREATE INDEX IF NOT EXISTS idx_knowledge_base_topic ON knowledge_base (topic, difficulty);
+CREATE INDEX IF NOT EXISTS idx_knowledge_base_created_at ON knowledge_base (created_at);
+CREATE INDEX IF NOT EXISTS idx_knowledge_base_dedup ON knowledge_base (collection, content_hash, title, source);
+CREATE INDEX IF NOT EXISTS idx_session_knowledge_chat_id ON session_knowledge (chat_id);
+CREATE INDEX IF NOT EXISTS idx_session_knowledge_created_at ON session_knowledge (created_at);
+-- 创建token用量表（每轮生成一条）
+CREATE TABLE IF NOT EXISTS "public"."token_usage" (
+     "id" BIGSERIAL PRIMARY KEY,
+     "chat_id" VARCHAR(255) NOT NULL,
+     "turn" BIGINT NOT NULL,
+     "state" VARCHAR(50) NOT NULL DEFAULT '',
+     "provider" VARCHAR(100) NOT NULL DEFAULT '',
+     "model" VARCHAR(255) NOT NULL DEFAULT '',
+     "prompt_tokens" INT NOT NULL DEFAULT 0,
+     "completion_tokens" INT NOT NULL DEFAULT 0,
+     "total_tokens" INT NOT NULL DEFAULT 0,
+     "estimated" BOOLEAN NOT NULL DEFAULT FALSE,
+     "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_token_usage_chat_id ON token_usage (chat_id);
+CREATE INDEX IF NOT EXISTS idx_token_usage_created_at ON token_usage (created_at);
+
+-- 创建题库表
+CREATE TABLE IF NOT EXISTS "public"."question_bank" (
+     "id" BIGSERIAL PRIMARY KEY,
+     "topic" VARCHAR(100) NOT NULL,
+     "difficulty" VARCHAR(20) NOT NULL DEFAULT 'middle',
+     "content" TEXT NOT NULL,
+     "reference_answer" TEXT NOT NULL DEFAULT '',
+     "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    );
+
+-- 创建面试评分表
+CREATE TABLE IF NOT EXISTS "public"."interview_score" (
+     "id" BIGSERIAL PRIMARY KEY,
+     "chat_id" VARCHAR(255) NOT NULL,
+     "dimension" VARCHAR(100) NOT NULL,
+     "score" DOUBLE PRECISION NOT NULL,
+     "max_score" DOUBLE PRECISION NOT NULL,
+     "comment" TEXT NOT NULL DEFAULT '',
+     "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_question_bank_topic ON question_bank (topic, difficulty);
+CREATE INDEX IF NOT EXISTS idx_interview_score_chat_id ON interview_score (chat_id);
+
+-- 题库示例数据
+INSERT INTO question_bank (topic, difficulty, content, reference_answer) VALUES
+    ('concurrency', 'junior', 'goroutine 和操作系统线程有什么区别？', '栈大小可增长、由运行时调度（GMP）、创建与切换成本低'),
+    ('concurrency', 'middle', '无缓冲 channel 和有缓冲 channel 的区别是什么？向已关闭的 channel 发送数据会怎样？', '同步与异步语义；向已关闭channel发送会panic，接收返回零值'),
+    ('concurrency', 'senior', '请描述 GMP 调度模型，以及 work stealing 和 hand off 机制。', 'G/M/P 职责、本地与全局队列、系统调用时P的移交、空闲P窃取其他P的G'),
+    ('memory', 'middle', '什么情况下变量会逃逸到堆上？如何分析？', '返回局部变量指针、闭包引用、interface 动态类型、大对象；go build -gcflags=-m'),
+    ('gc', 'senior', 'Go 的垃圾回收使用了什么算法？写屏障的作用是什么？', '三色标记并发清除、混合写屏障保证不丢失对象、STW 阶段'),
+    ('basics', 'junior', 'slice 和数组的区别是什么？append 扩容规则是怎样的？', 'slice 为引用底层数组的描述符；容量不足时按规则扩容并拷贝'),
+    ('basics', 'middle', 'interface 的底层结构是什么？nil interface 与值为 nil 的 interface 有何区别？', 'iface/eface 结构；类型与值均为nil才等于nil');
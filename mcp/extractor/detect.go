@@ -0,0 +1,111 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const sniffLen = 8192
+
+var (
+	pdfMagic = []byte("%PDF-")
+	zipMagic = []byte("PK\x03\x04")
+	utf8BOM  = []byte("\xef\xbb\xbf")
+
+	// 较明确的 Markdown 标记：标题、代码块、链接/图片、表格分隔行
+	strongMarkdown = regexp.MustCompile("(?m)^#{1,6}\\s+\\S|^(```|~~~)|!?\\[[^\\]\\n]+\\]\\([^)\\s]+\\)|^\\|?\\s*:?-{3,}:?\\s*\\|")
+	// 普通文本中也可能出现的标记：列表、引用、加粗、行内代码，需同时出现两种以上
+	weakMarkdown = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^\s*[-*+]\s+\S`),
+		regexp.MustCompile(`(?m)^>\s?\S`),
+		regexp.MustCompile(`\*\*[^*\n]+\*\*|__[^_\n]+__`),
+		regexp.MustCompile("`[^`\\n]+`"),
+	}
+)
+
+// Detect 根据文件内容识别格式，不依赖客户端声明的类型或扩展名
+func Detect(r io.ReaderAt, size int64) (Format, error) {
+	head := make([]byte, min(size, sniffLen))
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	head = head[:n]
+
+	switch {
+	case len(head) == 0:
+		return "", ErrUnsupported
+	// PDF 文件头允许出现在前1024字节内
+	case bytes.Contains(head[:min(len(head), 1024)], pdfMagic):
+		return FormatPDF, nil
+	case bytes.HasPrefix(head, zipMagic):
+		return detectZip(r, size)
+	}
+	return detectText(head, int64(len(head)) < size)
+}
+
+// detectZip 区分 DOCX 与 EPUB
+func detectZip(r io.ReaderAt, size int64) (Format, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", ErrUnsupported
+	}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			return FormatDOCX, nil
+		case "META-INF/container.xml":
+			return FormatEPUB, nil
+		case "mimetype":
+			if data, err := readZipFile(f); err == nil && strings.TrimSpace(string(data)) == "application/epub+zip" {
+				return FormatEPUB, nil
+			}
+		}
+	}
+	return "", ErrUnsupported
+}
+
+// detectText 识别 HTML、Markdown 与纯文本；含 NUL 或非 UTF-8 内容视为二进制文件
+func detectText(head []byte, truncated bool) (Format, error) {
+	head = bytes.TrimPrefix(head, utf8BOM)
+	if truncated {
+		// 采样可能截断在多字节字符中间
+		for i := 0; i < utf8.UTFMax-1 && len(head) > 0 && !utf8.Valid(head); i++ {
+			head = head[:len(head)-1]
+		}
+	}
+	if bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(head) {
+		return "", ErrUnsupported
+	}
+
+	if strings.HasPrefix(http.DetectContentType(head), "text/html") || isXHTML(head) {
+		return FormatHTML, nil
+	}
+	if isMarkdown(string(head)) {
+		return FormatMarkdown, nil
+	}
+	return FormatText, nil
+}
+
+func isXHTML(head []byte) bool {
+	s := strings.ToLower(string(head[:min(len(head), 512)]))
+	return strings.HasPrefix(strings.TrimSpace(s), "<?xml") && strings.Contains(s, "<html")
+}
+
+func isMarkdown(s string) bool {
+	if strongMarkdown.MatchString(s) {
+		return true
+	}
+	matched := 0
+	for _, re := range weakMarkdown {
+		if re.MatchString(s) {
+			matched++
+		}
+	}
+	return matched >= 2
+}
@@ -0,0 +1,92 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+// docxExtractor 解析 word/document.xml 中的正文，段落换行、表格单元格以制表符分隔
+type docxExtractor struct{}
+
+func (docxExtractor) Extract(ctx context.Context, r io.ReaderAt, size int64, opts Options, fn SectionFunc) (*Info, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	f := findZipFile(zr, "word/document.xml")
+	if f == nil {
+		return nil, errors.New("缺少 word/document.xml")
+	}
+	rc, err := openZipFile(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	text, err := docxText(rc)
+	if err != nil {
+		return nil, err
+	}
+	return nil, fn(&Section{Index: 1, Total: 1, Text: text})
+}
+
+func docxText(r io.Reader) (string, error) {
+	var buf bytes.Buffer
+	inText := false
+	cellDepth := 0
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return buf.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tc":
+				cellDepth++
+			case "tab":
+				buf.WriteByte('\t')
+			case "br", "cr":
+				buf.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				// 单元格内的段落以空格连接，保持一行一条表格记录
+				if cellDepth > 0 {
+					buf.WriteByte(' ')
+				} else {
+					buf.WriteByte('\n')
+				}
+			case "tc":
+				cellDepth = max(cellDepth-1, 0)
+				trimTrailing(&buf, " ")
+				buf.WriteByte('\t')
+			case "tr":
+				trimTrailing(&buf, "\t")
+				buf.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				buf.Write(t)
+			}
+		}
+	}
+}
+
+func trimTrailing(buf *bytes.Buffer, cutset string) {
+	buf.Truncate(len(bytes.TrimRight(buf.Bytes(), cutset)))
+}
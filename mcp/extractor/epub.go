@@ -0,0 +1,133 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+)
+
+const (
+	maxEpubChapters = 5000      // spine 章节数上限
+	maxEpubBytes    = 256 << 20 // 单个文档所有章节解压后的累计大小上限，spine 可重复引用同一文件
+)
+
+// errEpubTooLarge 章节数或解压后的累计大小超过上限
+var errEpubTooLarge = errors.New("EPUB章节过多或解压后内容过大")
+
+// epubExtractor 按 spine 顺序逐章节提取，每个章节为一个部分
+type epubExtractor struct{}
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type epubPackage struct {
+	Manifest []struct {
+		ID   string `xml:"id,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+func (epubExtractor) Extract(ctx context.Context, r io.ReaderAt, size int64, opts Options, fn SectionFunc) (*Info, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	opfPath, err := epubRootfile(zr)
+	if err != nil {
+		return nil, err
+	}
+	var pkg epubPackage
+	if err := unmarshalZipXML(zr, opfPath, &pkg); err != nil {
+		return nil, err
+	}
+	if len(pkg.Spine) == 0 {
+		return nil, errors.New("EPUB缺少章节目录")
+	}
+	if len(pkg.Spine) > maxEpubChapters {
+		return nil, fmt.Errorf("%w：%d 个章节，上限 %d", errEpubTooLarge, len(pkg.Spine), maxEpubChapters)
+	}
+
+	hrefs := make(map[string]string, len(pkg.Manifest))
+	for _, item := range pkg.Manifest {
+		hrefs[item.ID] = item.Href
+	}
+	base := path.Dir(opfPath)
+	total := len(pkg.Spine)
+	budget := int64(maxEpubBytes)
+	for i, ref := range pkg.Spine {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		text, chapterErr := epubChapter(zr, base, hrefs[ref.IDRef], &budget)
+		if errors.Is(chapterErr, errEpubTooLarge) {
+			return nil, chapterErr
+		}
+		if err := fn(&Section{Index: i + 1, Total: total, Text: text, Err: chapterErr}); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// epubRootfile 从 META-INF/container.xml 读取 OPF 文件路径
+func epubRootfile(zr *zip.Reader) (string, error) {
+	var container epubContainer
+	if err := unmarshalZipXML(zr, "META-INF/container.xml", &container); err != nil {
+		return "", err
+	}
+	if len(container.Rootfiles) == 0 || container.Rootfiles[0].FullPath == "" {
+		return "", errors.New("EPUB缺少rootfile")
+	}
+	return container.Rootfiles[0].FullPath, nil
+}
+
+// epubChapter 读取并转换一个章节，解压后的大小从 budget 中扣除，超出时返回 errEpubTooLarge
+func epubChapter(zr *zip.Reader, base, href string, budget *int64) (string, error) {
+	if href == "" {
+		return "", errors.New("章节不在manifest中")
+	}
+	name, err := url.PathUnescape(href)
+	if err != nil {
+		return "", err
+	}
+	f := findZipFile(zr, path.Join(base, name))
+	if f == nil {
+		return "", fmt.Errorf("缺少章节文件 %s", href)
+	}
+	if f.UncompressedSize64 > uint64(*budget) {
+		return "", fmt.Errorf("%w：累计超过 %d 字节", errEpubTooLarge, maxEpubBytes)
+	}
+	data, err := readZipFile(f)
+	if err != nil {
+		return "", err
+	}
+	if *budget -= int64(len(data)); *budget < 0 {
+		return "", fmt.Errorf("%w：累计超过 %d 字节", errEpubTooLarge, maxEpubBytes)
+	}
+	return htmlText(bytes.NewReader(data))
+}
+
+func unmarshalZipXML(zr *zip.Reader, name string, v any) error {
+	f := findZipFile(zr, name)
+	if f == nil {
+		return fmt.Errorf("EPUB缺少 %s", name)
+	}
+	data, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, v)
+}
@@ -0,0 +1,149 @@
+// Package extractor 按内容识别文档格式并提取文本，支持 PDF、DOCX、EPUB、HTML、Markdown 与纯文本
+package extractor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Format 文档格式
+type Format string
+
+const (
+	FormatPDF      Format = "pdf"
+	FormatDOCX     Format = "docx"
+	FormatEPUB     Format = "epub"
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+	FormatText     Format = "text"
+)
+
+var (
+	// ErrUnsupported 无法识别或不支持的格式
+	ErrUnsupported = errors.New("不支持的文件格式，仅支持PDF、DOCX、EPUB、HTML、Markdown与UTF-8文本")
+	// ErrPassword PDF已加密且未提供密码或密码错误
+	ErrPassword = errors.New("PDF已加密，密码错误或未提供密码")
+)
+
+// Section 文档的一个部分：PDF按页、EPUB按章节，其余格式整篇为一个部分
+type Section struct {
+	Index      int // 从1开始，PDF为页码
+	Total      int // 文档总页数
+	Seq        int // 本次解析中的序号（从1开始），指定页码范围时与 Index 不同
+	Count      int // 本次解析的部分数，指定页码范围时少于 Total
+	Text       string
+	Err        error   // 该部分的解析错误
+	OCR        bool    // 文本由OCR识别
+	Confidence float64 // OCR平均置信度（0-1）
+}
+
+// SectionFunc 逐部分回调，返回错误时停止解析
+type SectionFunc func(s *Section) error
+
+// Options 解析选项
+type Options struct {
+	Layout      Layout     // 输出版式，默认纯文本
+	OCR         OCR        // 扫描页OCR引擎，为nil时不识别
+	OCRMinChars int        // PDF页面提取到的文本少于该字符数时视为扫描页，默认10
+	Pages       PageRanges // 只解析的页码范围，目前用于PDF，为空时解析全部
+	Password    string     // 加密PDF的密码
+}
+
+func (o Options) ocrMinChars() int {
+	if o.OCRMinChars > 0 {
+		return o.OCRMinChars
+	}
+	return 10
+}
+
+// Extractor 文档文本提取器，返回文档信息（不提供时为nil）；
+// 单个部分失败通过 fn 报告，文件整体无法解析或 fn 返回错误时返回该错误
+type Extractor interface {
+	Extract(ctx context.Context, r io.ReaderAt, size int64, opts Options, fn SectionFunc) (*Info, error)
+}
+
+var extractors = map[Format]Extractor{
+	FormatPDF:      pdfExtractor{},
+	FormatDOCX:     docxExtractor{},
+	FormatEPUB:     epubExtractor{},
+	FormatHTML:     htmlExtractor{},
+	FormatMarkdown: markdownExtractor{},
+	FormatText:     textExtractor{},
+}
+
+// For 返回格式对应的提取器
+func For(format Format) (Extractor, error) {
+	if e, ok := extractors[format]; ok {
+		return e, nil
+	}
+	return nil, ErrUnsupported
+}
+
+// Extract 识别格式后逐部分解析，返回的文档信息不为nil，出错时至少包含已识别的格式
+func Extract(ctx context.Context, r io.ReaderAt, size int64, opts Options, fn SectionFunc) (*Info, error) {
+	format, err := Detect(r, size)
+	if err != nil {
+		return &Info{}, err
+	}
+	e, err := For(format)
+	if err != nil {
+		return &Info{Format: format}, err
+	}
+
+	total := 0
+	info, err := e.Extract(ctx, r, size, opts, func(s *Section) error {
+		if s.Seq == 0 {
+			s.Seq, s.Count = s.Index, s.Total
+		}
+		total = s.Total
+		return fn(s)
+	})
+	if info == nil {
+		info = &Info{}
+	}
+	info.Format = format
+	if info.Pages == 0 {
+		info.Pages = total
+	}
+	return info, err
+}
+
+// OCRPage 经OCR识别的页面
+type OCRPage struct {
+	Page       int
+	Confidence float64
+}
+
+// Document 全文解析结果
+type Document struct {
+	Info
+	Content  string
+	OCRPages []OCRPage
+}
+
+// ExtractText 识别格式并返回全文，任一部分解析失败时返回错误
+func ExtractText(ctx context.Context, r io.ReaderAt, size int64, opts Options) (*Document, error) {
+	doc := &Document{}
+	var sb strings.Builder
+	info, err := Extract(ctx, r, size, opts, func(s *Section) error {
+		if s.Err != nil {
+			return s.Err
+		}
+		if s.OCR {
+			doc.OCRPages = append(doc.OCRPages, OCRPage{Page: s.Index, Confidence: s.Confidence})
+		}
+		if text := strings.TrimSpace(s.Text); text != "" {
+			sb.WriteString(text)
+			sb.WriteString("\n\n")
+		}
+		return nil
+	})
+	doc.Info = *info
+	if err != nil {
+		return doc, err
+	}
+	doc.Content = sb.String()
+	return doc, nil
+}
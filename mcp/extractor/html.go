@@ -0,0 +1,103 @@
+package extractor
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlExtractor 提取可见文本，忽略 head、脚本与样式
+type htmlExtractor struct{}
+
+func (htmlExtractor) Extract(ctx context.Context, r io.ReaderAt, size int64, opts Options, fn SectionFunc) (*Info, error) {
+	text, err := htmlText(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, err
+	}
+	return nil, fn(&Section{Index: 1, Total: 1, Text: text})
+}
+
+var (
+	skippedTags = map[atom.Atom]bool{
+		atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true, atom.Svg: true,
+	}
+	blockTags = map[atom.Atom]bool{
+		atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true, atom.Hr: true,
+		atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+		atom.Ul: true, atom.Ol: true, atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Table: true,
+		atom.Section: true, atom.Article: true, atom.Header: true, atom.Footer: true, atom.Nav: true,
+		atom.Blockquote: true, atom.Pre: true, atom.Figure: true, atom.Figcaption: true,
+	}
+	cellTags = map[atom.Atom]bool{atom.Td: true, atom.Th: true}
+
+	spaces     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+func htmlText(r io.Reader) (string, error) {
+	var sb strings.Builder
+	skipDepth, preDepth := 0, 0
+	z := html.NewTokenizer(r)
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return "", err
+			}
+			return cleanText(sb.String()), nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			if skippedTags[a] {
+				if tt == html.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			if a == atom.Pre {
+				preDepth++
+			}
+			if blockTags[a] {
+				sb.WriteByte('\n')
+			} else if cellTags[a] {
+				sb.WriteByte('\t')
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			if skippedTags[a] {
+				skipDepth = max(skipDepth-1, 0)
+				continue
+			}
+			if a == atom.Pre {
+				preDepth = max(preDepth-1, 0)
+			}
+			if blockTags[a] {
+				sb.WriteByte('\n')
+			}
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			text := string(z.Text())
+			if preDepth == 0 {
+				text = spaces.ReplaceAllString(text, " ")
+			}
+			sb.WriteString(text)
+		}
+	}
+}
+
+// cleanText 去除行首尾空白并合并多余空行
+func cleanText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
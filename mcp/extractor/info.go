@@ -0,0 +1,105 @@
+package extractor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Info 文档信息；标题、作者、日期与目录目前仅PDF提供，Pages 为文档总页数（EPUB为章节数，其余格式为1）
+type Info struct {
+	Format   Format
+	Title    string
+	Author   string
+	Subject  string
+	Keywords string
+	Creator  string
+	Producer string
+	Created  time.Time // 零值表示未知
+	Modified time.Time
+	Pages    int
+	Outline  []*OutlineItem // 目录（书签）树
+}
+
+// OutlineItem 目录项
+type OutlineItem struct {
+	Title    string
+	Page     int // 目标页码（从1开始），0 表示没有目标页
+	Children []*OutlineItem
+}
+
+// PageRange 页码范围（含两端），End 为0表示到最后一页
+type PageRange struct {
+	Start int
+	End   int
+}
+
+// PageRanges 多个页码范围，为空时表示全部页面
+type PageRanges []PageRange
+
+// ParsePageRanges 解析页码范围，如 "1-3,5,8-"，空字符串表示全部页面
+func ParsePageRanges(s string) (PageRanges, error) {
+	var ranges PageRanges
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || start < 1 {
+			return nil, fmt.Errorf("无效的页码范围: %s", part)
+		}
+		r := PageRange{Start: start, End: start}
+		if isRange {
+			r.End = 0
+			if to = strings.TrimSpace(to); to != "" {
+				if r.End, err = strconv.Atoi(to); err != nil || r.End < start {
+					return nil, fmt.Errorf("无效的页码范围: %s", part)
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// Contains 页码是否在范围内
+func (p PageRanges) Contains(page int) bool {
+	if len(p) == 0 {
+		return true
+	}
+	for _, r := range p {
+		if page >= r.Start && (r.End == 0 || page <= r.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// Select 返回 1..total 中位于范围内的页码（升序、不重复）
+func (p PageRanges) Select(total int) []int {
+	var pages []int
+	for i := 1; i <= total; i++ {
+		if p.Contains(i) {
+			pages = append(pages, i)
+		}
+	}
+	return pages
+}
+
+func (p PageRanges) String() string {
+	parts := make([]string, len(p))
+	for i, r := range p {
+		switch {
+		case r.End == 0:
+			parts[i] = fmt.Sprintf("%d-", r.Start)
+		case r.End == r.Start:
+			parts[i] = strconv.Itoa(r.Start)
+		default:
+			parts[i] = fmt.Sprintf("%d-%d", r.Start, r.End)
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,218 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Layout 输出版式
+type Layout string
+
+const (
+	LayoutText     Layout = "text"     // 纯文本（默认）
+	LayoutMarkdown Layout = "markdown" // 结构化Markdown：标题、列表、表格与代码块，目前用于PDF，Markdown文件返回原文
+)
+
+// ParseLayout 解析版式名称，空字符串为纯文本
+func ParseLayout(s string) (Layout, error) {
+	switch Layout(s) {
+	case "", LayoutText:
+		return LayoutText, nil
+	case LayoutMarkdown:
+		return LayoutMarkdown, nil
+	}
+	return "", fmt.Errorf("不支持的版式: %s", s)
+}
+
+// layoutLine 页面中的一行文本及其版式特征
+type layoutLine struct {
+	text  string
+	size  float64 // 行内字符的主要字号
+	x     float64 // 行首横坐标
+	mono  bool    // 等宽字体，视为代码
+	table int     // 所在表格序号，-1 表示不在表格内
+}
+
+const (
+	maxHeadingRunes = 80   // 标题行的最大长度
+	headingRatio    = 1.15 // 字号超过正文该倍数时视为标题
+)
+
+var (
+	bulletItem   = regexp.MustCompile(`^(?:[•●○◦▪■□·‣∙]\s*|[-*–]\s+)(.+)$`)
+	numberedItem = regexp.MustCompile(`^(\d{1,3})(?:[.)]\s+|[、．]\s*)(.+)$`)
+	sentenceEnd  = regexp.MustCompile(`[。；;，,:：.]$`)
+)
+
+// renderMarkdown 将页面的行与表格转换为Markdown；表格在其第一行所在位置输出，连续的等宽字体行合并为代码块
+func renderMarkdown(lines []layoutLine, tables [][][]string) string {
+	body := bodyFontSize(lines)
+	var blocks []string
+	var code []layoutLine
+	flushCode := func() {
+		if len(code) > 0 {
+			blocks = append(blocks, codeBlock(code))
+			code = nil
+		}
+	}
+	emitted := make(map[int]bool)
+	var para []string
+	flushPara := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, strings.Join(para, "\n"))
+			para = nil
+		}
+	}
+
+	for _, l := range lines {
+		if l.table >= 0 {
+			flushCode()
+			flushPara()
+			if !emitted[l.table] && l.table < len(tables) {
+				emitted[l.table] = true
+				if t := markdownTable(tables[l.table]); t != "" {
+					blocks = append(blocks, t)
+				}
+			}
+			continue
+		}
+		text := strings.TrimSpace(l.text)
+		// 代码中的空行不结束代码块
+		if l.mono || text == "" && len(code) > 0 {
+			flushPara()
+			code = append(code, l)
+			continue
+		}
+		flushCode()
+
+		if text == "" {
+			flushPara()
+			continue
+		}
+		if level := headingLevel(l, text, body); level > 0 {
+			flushPara()
+			blocks = append(blocks, strings.Repeat("#", level)+" "+text)
+			continue
+		}
+		if m := bulletItem.FindStringSubmatch(text); m != nil {
+			para = append(para, "- "+m[1])
+			continue
+		}
+		if m := numberedItem.FindStringSubmatch(text); m != nil {
+			para = append(para, m[1]+". "+m[2])
+			continue
+		}
+		para = append(para, text)
+	}
+	flushCode()
+	flushPara()
+
+	// 没有对应文本行的表格（如整页表格的文本未被识别为行）追加在末尾
+	for i, rows := range tables {
+		if t := markdownTable(rows); !emitted[i] && t != "" {
+			blocks = append(blocks, t)
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// bodyFontSize 按字符数统计出现最多的字号作为正文字号
+func bodyFontSize(lines []layoutLine) float64 {
+	counts := make(map[float64]int)
+	for _, l := range lines {
+		if l.table < 0 && !l.mono && l.size > 0 {
+			counts[math.Round(l.size*2)/2] += utf8.RuneCountInString(l.text)
+		}
+	}
+	var body float64
+	best := 0
+	for size, n := range counts {
+		if n > best || n == best && size < body {
+			body, best = size, n
+		}
+	}
+	return body
+}
+
+// headingLevel 按字号与正文字号之比确定标题级别，0 表示不是标题
+func headingLevel(l layoutLine, text string, body float64) int {
+	if body <= 0 || l.size < body*headingRatio || utf8.RuneCountInString(text) > maxHeadingRunes || sentenceEnd.MatchString(text) {
+		return 0
+	}
+	switch ratio := l.size / body; {
+	case ratio >= 1.8:
+		return 1
+	case ratio >= 1.4:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// codeBlock 按行首横坐标还原缩进
+func codeBlock(lines []layoutLine) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1].text) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	minX := math.MaxFloat64
+	for _, l := range lines {
+		if l.size > 0 {
+			minX = math.Min(minX, l.x)
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	for _, l := range lines {
+		// 等宽字体字符宽度约为字号的0.6倍，空行没有字号
+		indent := 0
+		if l.size > 0 {
+			indent = int(math.Round((l.x - minX) / (l.size * 0.6)))
+		}
+		sb.WriteString(strings.Repeat(" ", indent))
+		sb.WriteString(strings.TrimSpace(l.text))
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("```")
+	return sb.String()
+}
+
+// markdownTable 第一行作为表头
+func markdownTable(rows [][]string) string {
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	if cols == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for i, row := range rows {
+		sb.WriteByte('|')
+		for c := 0; c < cols; c++ {
+			cell := ""
+			if c < len(row) {
+				cell = row[c]
+			}
+			fmt.Fprintf(&sb, " %s |", tableCell(cell))
+		}
+		sb.WriteByte('\n')
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", cols) + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func tableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// lineIndex 返回字节偏移所在的行号，starts 为各行起始偏移
+func lineIndex(starts []int, offset int) int {
+	return sort.Search(len(starts), func(i int) bool { return starts[i] > offset }) - 1
+}
@@ -0,0 +1,139 @@
+package extractor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OCR 图片文字识别引擎，输入为PNG图片
+type OCR interface {
+	Recognize(ctx context.Context, png []byte) (*OCRResult, error)
+}
+
+// OCRResult 识别结果，Confidence 为平均置信度（0-1）
+type OCRResult struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+}
+
+type tesseract struct {
+	path    string
+	lang    string
+	timeout time.Duration
+}
+
+// NewTesseract 调用本地 tesseract 命令识别，lang 如 chi_sim+eng
+func NewTesseract(path, lang string, timeout time.Duration) OCR {
+	return &tesseract{path: path, lang: lang, timeout: timeout}
+}
+
+func (t *tesseract) Recognize(ctx context.Context, png []byte) (*OCRResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	// tsv 输出包含每个单词的位置与置信度
+	cmd := exec.CommandContext(ctx, t.path, "stdin", "stdout", "-l", t.lang, "tsv")
+	cmd.Stdin = bytes.NewReader(png)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("tesseract: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("tesseract: %w", err)
+	}
+	return parseTesseractTSV(out), nil
+}
+
+// parseTesseractTSV 按块、段落、行还原文本，置信度取全部单词的平均值
+// 列：level page_num block_num par_num line_num word_num left top width height conf text
+func parseTesseractTSV(out []byte) *OCRResult {
+	var sb strings.Builder
+	var confSum float64
+	var words int
+	var lastPar, lastLine string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		cols := strings.Split(scanner.Text(), "\t")
+		if len(cols) < 12 || cols[0] != "5" {
+			continue
+		}
+		conf, err := strconv.ParseFloat(cols[10], 64)
+		text := strings.TrimSpace(cols[11])
+		if err != nil || conf < 0 || text == "" {
+			continue
+		}
+
+		par := cols[2] + "." + cols[3]
+		line := par + "." + cols[4]
+		switch {
+		case sb.Len() == 0:
+		case par != lastPar:
+			sb.WriteString("\n\n")
+		case line != lastLine:
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(' ')
+		}
+		lastPar, lastLine = par, line
+
+		sb.WriteString(text)
+		confSum += conf
+		words++
+	}
+
+	result := &OCRResult{Text: sb.String()}
+	if words > 0 {
+		result.Confidence = confSum / float64(words) / 100
+	}
+	return result
+}
+
+type httpOCR struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPOCR 通过HTTP服务识别：POST PNG图片（Content-Type: image/png），
+// 响应JSON {"text": "...", "confidence": 0.9}
+func NewHTTPOCR(url string, headers map[string]string, timeout time.Duration) OCR {
+	return &httpOCR{url: url, headers: headers, client: &http.Client{Timeout: timeout}}
+}
+
+func (h *httpOCR) Recognize(ctx context.Context, png []byte) (*OCRResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(png))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "image/png")
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("ocr http status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result OCRResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode ocr response: %w", err)
+	}
+	return &result, nil
+}
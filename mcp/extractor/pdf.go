@@ -0,0 +1,257 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/unidoc/unipdf/v3/core"
+	pdfextractor "github.com/unidoc/unipdf/v3/extractor"
+	"github.com/unidoc/unipdf/v3/model"
+	"github.com/unidoc/unipdf/v3/render"
+)
+
+// ocrRenderWidth 扫描页渲染宽度（像素），A4约240dpi
+const ocrRenderWidth = 2000
+
+// pdfExtractor 使用UniPDF逐页提取，需先设置 UniPDF license；
+// 配置OCR时，提取不到文本的页面（扫描件）渲染为图片后识别
+type pdfExtractor struct{}
+
+func (pdfExtractor) Extract(ctx context.Context, r io.ReaderAt, size int64, opts Options, fn SectionFunc) (*Info, error) {
+	pdfReader, err := openPDF(io.NewSectionReader(r, 0, size), opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	numPages, err := pdfReader.GetNumPages()
+	if err != nil {
+		return nil, err
+	}
+	info := pdfInfo(pdfReader, numPages)
+	pages := opts.Pages.Select(numPages)
+	if len(pages) == 0 {
+		return info, fmt.Errorf("页码范围 %s 超出文档页数（共%d页）", opts.Pages, numPages)
+	}
+
+	for seq, i := range pages {
+		if err := ctx.Err(); err != nil {
+			return info, err
+		}
+		s := &Section{Index: i, Total: numPages, Seq: seq + 1, Count: len(pages)}
+		page, err := pdfReader.GetPage(i)
+		if err != nil {
+			s.Err = err
+		} else {
+			s.Text, s.Err = extractPage(page, opts.Layout)
+		}
+		if s.Err == nil && opts.OCR != nil && utf8.RuneCountInString(strings.TrimSpace(s.Text)) < opts.ocrMinChars() {
+			ocrPage(ctx, opts.OCR, page, s)
+		}
+		if err := fn(s); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// openPDF 打开PDF，加密文件先尝试空密码（仅限制权限的文件），再使用提供的密码
+func openPDF(rs io.ReadSeeker, password string) (*model.PdfReader, error) {
+	pdfReader, err := model.NewPdfReader(rs)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := pdfReader.IsEncrypted()
+	if err != nil || !encrypted {
+		return pdfReader, err
+	}
+	for _, pw := range []string{"", password} {
+		if ok, err := pdfReader.Decrypt([]byte(pw)); err == nil && ok {
+			return pdfReader, nil
+		}
+	}
+	return nil, ErrPassword
+}
+
+// pdfInfo 读取文档信息字典与目录，读取失败的部分留空
+func pdfInfo(pdfReader *model.PdfReader, numPages int) *Info {
+	info := &Info{Pages: numPages}
+	if pi, err := pdfReader.GetPdfInfo(); err == nil && pi != nil {
+		info.Title = pdfString(pi.Title)
+		info.Author = pdfString(pi.Author)
+		info.Subject = pdfString(pi.Subject)
+		info.Keywords = pdfString(pi.Keywords)
+		info.Creator = pdfString(pi.Creator)
+		info.Producer = pdfString(pi.Producer)
+		if pi.CreationDate != nil {
+			info.Created = pi.CreationDate.ToGoTime()
+		}
+		if pi.ModifiedDate != nil {
+			info.Modified = pi.ModifiedDate.ToGoTime()
+		}
+	}
+	if outline, err := pdfReader.GetOutlines(); err == nil && outline != nil {
+		info.Outline = outlineItems(outline.Entries, numPages)
+	}
+	return info
+}
+
+func pdfString(s *core.PdfObjectString) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(s.Decoded())
+}
+
+// outlineItems 转换目录树，unipdf 的目标页为从0开始的页序号
+func outlineItems(entries []*model.OutlineItem, numPages int) []*OutlineItem {
+	var items []*OutlineItem
+	for _, e := range entries {
+		if e == nil {
+			continue
+		}
+		item := &OutlineItem{Title: strings.TrimSpace(e.Title), Children: outlineItems(e.Entries, numPages)}
+		if page := int(e.Dest.Page) + 1; e.Dest.PageObj != nil && page >= 1 && page <= numPages {
+			item.Page = page
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func extractPage(page *model.PdfPage, layout Layout) (string, error) {
+	ex, err := pdfextractor.New(page)
+	if err != nil {
+		return "", err
+	}
+	if layout != LayoutMarkdown {
+		return ex.ExtractText()
+	}
+
+	pt, _, _, err := ex.ExtractPageText()
+	if err != nil {
+		return "", err
+	}
+	tables := pt.Tables()
+	rows := make([][][]string, len(tables))
+	for i, t := range tables {
+		for _, cells := range t.Cells {
+			row := make([]string, len(cells))
+			for j, cell := range cells {
+				row[j] = cell.Text
+			}
+			rows[i] = append(rows[i], row)
+		}
+	}
+	return renderMarkdown(pageLines(pt.Text(), pt.Marks().Elements(), tables), rows), nil
+}
+
+// pageLines 按换行切分页面文本，并根据各行字符的字体、字号与位置确定版式特征
+func pageLines(text string, marks []pdfextractor.TextMark, tables []pdfextractor.TextTable) []layoutLine {
+	starts := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	lines := make([]layoutLine, len(starts))
+	sizes := make([]map[float64]int, len(starts))
+	monoChars := make([]int, len(starts))
+	inTable := make([]map[int]int, len(starts))
+	chars := make([]int, len(starts))
+	for i := range lines {
+		end := len(text)
+		if i+1 < len(starts) {
+			end = starts[i+1] - 1
+		}
+		lines[i] = layoutLine{text: text[starts[i]:end], x: math.MaxFloat64, table: -1}
+		sizes[i] = make(map[float64]int)
+		inTable[i] = make(map[int]int)
+	}
+
+	for _, m := range marks {
+		if m.Meta || strings.TrimSpace(m.Text) == "" {
+			continue
+		}
+		i := lineIndex(starts, m.Offset)
+		if i < 0 {
+			continue
+		}
+		chars[i]++
+		sizes[i][math.Round(m.FontSize*2)/2]++
+		lines[i].x = math.Min(lines[i].x, m.BBox.Llx)
+		if m.Font != nil && isMonospace(m.Font.BaseFont()) {
+			monoChars[i]++
+		}
+		cx, cy := (m.BBox.Llx+m.BBox.Urx)/2, (m.BBox.Lly+m.BBox.Ury)/2
+		for t, table := range tables {
+			if cx >= table.Llx && cx <= table.Urx && cy >= table.Lly && cy <= table.Ury {
+				inTable[i][t]++
+				break
+			}
+		}
+	}
+
+	for i := range lines {
+		if chars[i] == 0 {
+			lines[i].x = 0
+			continue
+		}
+		best := 0
+		for size, n := range sizes[i] {
+			if n > best {
+				lines[i].size, best = size, n
+			}
+		}
+		lines[i].mono = monoChars[i]*2 > chars[i]
+		for t, n := range inTable[i] {
+			if n*2 > chars[i] {
+				lines[i].table = t
+			}
+		}
+	}
+	return lines
+}
+
+var monospaceFonts = []string{"mono", "courier", "consolas", "menlo", "monaco", "inconsolata", "code", "fixed"}
+
+func isMonospace(font string) bool {
+	font = strings.ToLower(font)
+	for _, name := range monospaceFonts {
+		if strings.Contains(font, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ocrPage 渲染并识别页面；识别结果为空时保留原文本（如空白页）
+func ocrPage(ctx context.Context, engine OCR, page *model.PdfPage, s *Section) {
+	device := render.NewImageDevice()
+	device.OutputWidth = ocrRenderWidth
+	img, err := device.Render(page)
+	if err != nil {
+		s.Err = fmt.Errorf("渲染页面失败: %w", err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		s.Err = err
+		return
+	}
+
+	result, err := engine.Recognize(ctx, buf.Bytes())
+	if err != nil {
+		s.Err = fmt.Errorf("OCR识别失败: %w", err)
+		return
+	}
+	if strings.TrimSpace(result.Text) == "" {
+		return
+	}
+	s.Text, s.OCR, s.Confidence = result.Text, true, result.Confidence
+}
@@ -0,0 +1,88 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// textExtractor UTF-8 纯文本，去除BOM并统一换行符
+type textExtractor struct{}
+
+func (textExtractor) Extract(ctx context.Context, r io.ReaderAt, size int64, opts Options, fn SectionFunc) (*Info, error) {
+	text, err := readText(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return nil, fn(&Section{Index: 1, Total: 1, Text: text})
+}
+
+// markdownExtractor 纯文本版式去除 Markdown 标记，保留标题、列表与代码内容；Markdown 版式返回原文
+type markdownExtractor struct{}
+
+func (markdownExtractor) Extract(ctx context.Context, r io.ReaderAt, size int64, opts Options, fn SectionFunc) (*Info, error) {
+	text, err := readText(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Layout != LayoutMarkdown {
+		text = stripMarkdown(text)
+	}
+	return nil, fn(&Section{Index: 1, Total: 1, Text: text})
+}
+
+func readText(r io.ReaderAt, size int64) (string, error) {
+	data, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return "", err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+}
+
+var (
+	frontMatter  = regexp.MustCompile(`\A---\n(?s:.*?)\n---\n`)
+	htmlComment  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdHeading    = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdQuote      = regexp.MustCompile(`^\s*>\s?`)
+	mdRule       = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+	mdTableRule  = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	mdBold       = regexp.MustCompile(`\*\*([^*\n]+)\*\*|__([^_\n]+)__`)
+	mdInlineCode = regexp.MustCompile("`([^`\\n]+)`")
+)
+
+func stripMarkdown(s string) string {
+	s = frontMatter.ReplaceAllString(s, "")
+	s = htmlComment.ReplaceAllString(s, "")
+
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	inFence := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		// 代码块内容原样保留
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+		if mdRule.MatchString(line) || mdTableRule.MatchString(line) {
+			continue
+		}
+		line = mdHeading.ReplaceAllString(line, "$1")
+		line = mdQuote.ReplaceAllString(line, "")
+		line = mdImage.ReplaceAllString(line, "$1")
+		line = mdLink.ReplaceAllString(line, "$1")
+		line = mdBold.ReplaceAllString(line, "$1$2")
+		line = mdInlineCode.ReplaceAllString(line, "$1")
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
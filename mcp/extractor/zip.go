@@ -0,0 +1,42 @@
+package extractor
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+)
+
+// maxEntrySize 压缩包内单个文件解压后的大小上限，防止压缩炸弹
+const maxEntrySize = 64 << 20
+
+func openZipFile(f *zip.File) (io.ReadCloser, error) {
+	if f.UncompressedSize64 > maxEntrySize {
+		return nil, fmt.Errorf("%s 超过大小限制", f.Name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rc, maxEntrySize), rc}, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := openZipFile(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func findZipFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
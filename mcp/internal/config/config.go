@@ -1,8 +1,54 @@
 package config
 
-import "github.com/zeromicro/go-zero/zrpc"
+import (
+	"time"
+
+	"github.com/zeromicro/go-zero/zrpc"
+)
 
 type Config struct {
 	zrpc.RpcServerConf
 	UniPDFLicense string
+	MaxFileSize   int64 `json:",default=20971520"` // 上传与文档解析工具接受的最大文件字节数
+	MCPServer     MCPServerConfig
+	Knowledge     KnowledgeConfig
+	OCR           OCRConfig
+}
+
+// OCRConfig 扫描版PDF的OCR兜底：提取不到文本的页面渲染为图片后识别
+type OCRConfig struct {
+	Engine        string            `json:",default=none,options=none|tesseract|http"`
+	TesseractPath string            `json:",default=tesseract"`   // tesseract 可执行文件
+	Lang          string            `json:",default=chi_sim+eng"` // tesseract 语言包
+	URL           string            `json:",optional"`            // http 引擎地址
+	Headers       map[string]string `json:",optional"`            // http 引擎请求头，如鉴权
+	Timeout       time.Duration     `json:",default=60s"`         // 单页识别超时
+	MinChars      int               `json:",default=10"`          // 页面文本少于该字符数时视为扫描页
+}
+
+// MCPServerConfig Model Context Protocol 服务配置（streamable HTTP 传输，stdio 传输通过 -stdio 启动）
+type MCPServerConfig struct {
+	ListenOn     string        `json:",optional"`     // HTTP 监听地址，为空时不启用；非本机地址须同时配置 BearerToken
+	Path         string        `json:",default=/mcp"` // 端点路径
+	AllowOrigins []string      `json:",optional"`     // 允许的浏览器来源，为空时拒绝携带 Origin 头的请求（防止DNS重绑定）
+	SessionTTL   time.Duration `json:",default=30m"`  // 会话空闲过期时间
+	BearerToken  string        `json:",optional"`     // 非空时要求请求携带 Authorization: Bearer <token>
+}
+
+// KnowledgeConfig 知识库与题库数据库，Host 为空时不提供 search_knowledge、search_questions 工具与题库资源
+type KnowledgeConfig struct {
+	Host     string `json:",optional"`
+	Port     int    `json:",default=5432"`
+	DBName   string `json:",optional"`
+	User     string `json:",optional"`
+	Password string `json:",optional"`
+	MaxConn  int    `json:",default=5"`
+
+	// 是否允许 search_questions 工具与题目资源返回参考答案，默认关闭，避免连接MCP的候选人侧客户端读取答案
+	ExposeAnswers bool `json:",optional"`
+
+	// 向量生成（OpenAI兼容接口），需与api服务写入知识库时使用的模型一致
+	BaseURL        string `json:",optional"`
+	ApiKey         string `json:",optional"`
+	EmbeddingModel string `json:",optional"`
 }
@@ -0,0 +1,46 @@
+package logic
+
+import (
+	"time"
+
+	"ai-gozero-agent/mcp/extractor"
+	"ai-gozero-agent/mcp/types/mcp"
+)
+
+// documentInfo 转换文档信息，未识别出格式时返回nil
+func documentInfo(info *extractor.Info) *mcp.DocumentInfo {
+	if info == nil || info.Format == "" {
+		return nil
+	}
+	return &mcp.DocumentInfo{
+		Format:       string(info.Format),
+		Title:        info.Title,
+		Author:       info.Author,
+		Subject:      info.Subject,
+		Keywords:     info.Keywords,
+		Creator:      info.Creator,
+		Producer:     info.Producer,
+		CreationDate: formatTime(info.Created),
+		ModDate:      formatTime(info.Modified),
+		PageCount:    int32(info.Pages),
+		Outline:      outlineItems(info.Outline),
+	}
+}
+
+func outlineItems(items []*extractor.OutlineItem) []*mcp.OutlineItem {
+	if len(items) == 0 {
+		return nil
+	}
+	out := make([]*mcp.OutlineItem, len(items))
+	for i, item := range items {
+		out[i] = &mcp.OutlineItem{Title: item.Title, Page: int32(item.Page), Children: outlineItems(item.Children)}
+	}
+	return out
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
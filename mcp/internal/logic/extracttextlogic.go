@@ -1,11 +1,9 @@
 package logic
 
 import (
-	"ai-gozero-agent/mcp/internal/utils"
+	"ai-gozero-agent/mcp/extractor"
 	"context"
-	"fmt"
-	"io"
-	"os"
+	"errors"
 
 	"ai-gozero-agent/mcp/internal/svc"
 	"ai-gozero-agent/mcp/types/mcp"
@@ -27,83 +25,34 @@ func NewExtractTextLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Extra
 	}
 }
 
-// 流式上传PDF并返回解析文本
+// 流式上传文档并返回解析文本，格式按内容识别
 func (l *ExtractTextLogic) ExtractText(stream mcp.PdfProcessor_ExtractTextServer) error {
-	// 接受元数据
-	firstChunk, err := stream.Recv()
-	if err != nil {
-		logx.Errorf("接受元数据失败: %v", err)
-		return err
-	}
-
-	meta := firstChunk.GetMetadate()
-	if meta == nil {
-		return stream.SendAndClose(&mcp.PdfResponse{Error: "缺少元数据"})
+	file, err := receiveFile(stream, l.svcCtx.Config.MaxFileSize)
+	if msg, ok := asReject(err); ok {
+		return stream.SendAndClose(&mcp.PdfResponse{Error: msg})
 	}
-
-	// 验证文件类型
-	if meta.MineType != "application/pdf" {
-		return stream.SendAndClose(&mcp.PdfResponse{Error: "仅支持PDF文件"})
-	}
-
-	// 创建临时文件
-	tmpFile, err := os.CreateTemp("", "pdf-*.pdf")
 	if err != nil {
-		logx.Errorf("创建临时文件失败: %v", err)
 		return err
 	}
-	defer os.Remove(tmpFile.Name())
-	defer tmpFile.Close()
+	defer file.close()
 
-	// 写入首块数据
-	if chunk := firstChunk.GetChunks(); chunk != nil {
-		if _, err := tmpFile.Write(chunk); err != nil {
-			logx.Errorf("写入临时文件失败: %v", err)
-			return err
-		}
+	// 识别格式并解析，扫描页按配置OCR识别
+	doc, err := extractor.ExtractText(l.ctx, file, file.size, file.options(l.svcCtx.Extract))
+	if errors.Is(err, extractor.ErrUnsupported) || errors.Is(err, extractor.ErrPassword) {
+		return stream.SendAndClose(&mcp.PdfResponse{Error: err.Error()})
 	}
-
-	// 接受并写入后续数据块
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			logx.Errorf("接收数据失败: %v", err)
-			return err
-		}
-
-		if chunk := req.GetChunks(); chunk != nil {
-			if _, err := tmpFile.Write(chunk); err != nil {
-				logx.Errorf("写入临时文件失败: %v", err)
-				return err
-			}
-		}
-	}
-
-	// 解析PDF
-	content, err := extractPdfText(tmpFile.Name())
 	if err != nil {
-		logx.Errorf("PDF解析失败: %v", err)
+		logx.Errorf("%s解析失败: %v", doc.Format, err)
 		return stream.SendAndClose(&mcp.PdfResponse{
-			Error: "PDF解析失败：" + err.Error(),
+			Error: "文档解析失败：" + err.Error(),
 		})
 	}
 
-	fmt.Println("消息解析完成，打包发送给API：", content)
+	l.Debugf("%s解析完成，文本长度 %d", doc.Format, len(doc.Content))
 
-	return stream.SendAndClose(&mcp.PdfResponse{
-		Content: content,
-	})
-}
-
-func extractPdfText(filePath string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
+	resp := &mcp.PdfResponse{Content: doc.Content, Info: documentInfo(&doc.Info)}
+	for _, p := range doc.OCRPages {
+		resp.OcrPages = append(resp.OcrPages, &mcp.OcrPage{Page: int32(p.Page), Confidence: float32(p.Confidence)})
 	}
-	defer file.Close()
-
-	return utils.ExtractPDFText(file)
+	return stream.SendAndClose(resp)
 }
@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"ai-gozero-agent/mcp/extractor"
+	"ai-gozero-agent/mcp/internal/svc"
+	"ai-gozero-agent/mcp/types/mcp"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ExtractTextStreamLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewExtractTextStreamLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ExtractTextStreamLogic {
+	return &ExtractTextStreamLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 流式上传文档，解析过程中逐页（EPUB逐章节，其余格式整篇）返回文本与进度
+func (l *ExtractTextStreamLogic) ExtractTextStream(stream mcp.PdfProcessor_ExtractTextStreamServer) error {
+	file, err := receiveFile(stream, l.svcCtx.Config.MaxFileSize)
+	if msg, ok := asReject(err); ok {
+		return stream.Send(&mcp.ExtractEvent{Error: msg, Done: true})
+	}
+	if err != nil {
+		return err
+	}
+	defer file.close()
+
+	// 逐页解析并推送，单页失败时在该页事件中返回错误并继续
+	var failed, ocrPages int
+	info, err := extractor.Extract(l.ctx, file, file.size, file.options(l.svcCtx.Extract), func(sec *extractor.Section) error {
+		if err := l.ctx.Err(); err != nil {
+			return err
+		}
+		e := &mcp.ExtractEvent{
+			Page:       int32(sec.Index),
+			TotalPages: int32(sec.Total),
+			Text:       sec.Text,
+			Percent:    float32(sec.Seq) * 100 / float32(sec.Count),
+			Ocr:        sec.OCR,
+			Confidence: float32(sec.Confidence),
+		}
+		if sec.OCR {
+			ocrPages++
+		}
+		if sec.Err != nil {
+			failed++
+			e.Error = sec.Err.Error()
+			l.Errorf("第%d页解析失败: %v", sec.Index, sec.Err)
+		}
+		return stream.Send(e)
+	})
+	if l.ctx.Err() != nil {
+		return l.ctx.Err()
+	}
+
+	done := &mcp.ExtractEvent{TotalPages: int32(info.Pages), Percent: 100, Done: true, Info: documentInfo(info)}
+	switch {
+	case errors.Is(err, extractor.ErrUnsupported) || errors.Is(err, extractor.ErrPassword):
+		done.Error = err.Error()
+	case err != nil:
+		l.Errorf("%s解析失败: %v", info.Format, err)
+		done.Error = "文档解析失败：" + err.Error()
+	default:
+		l.Infof("%s解析完成，共%d页，失败%d页，OCR识别%d页", info.Format, info.Pages, failed, ocrPages)
+	}
+	return stream.Send(done)
+}
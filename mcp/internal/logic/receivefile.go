@@ -0,0 +1,123 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"ai-gozero-agent/mcp/extractor"
+	"ai-gozero-agent/mcp/types/mcp"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// pdfReceiver ExtractText 与 ExtractTextStream 共用的上传流
+type pdfReceiver interface {
+	Recv() (*mcp.PdfRequest, error)
+}
+
+// rejectError 上传被拒绝（缺少元数据、超过大小），以响应中的错误信息返回给客户端
+type rejectError struct {
+	msg string
+}
+
+func (e *rejectError) Error() string {
+	return e.msg
+}
+
+// upload 已接收的文件及客户端指定的解析选项
+type upload struct {
+	*os.File
+	size     int64
+	layout   extractor.Layout
+	pages    extractor.PageRanges
+	password string
+}
+
+// options 在服务配置的解析选项上应用客户端指定的版式、页码范围与密码
+func (u *upload) options(base extractor.Options) extractor.Options {
+	base.Layout = u.layout
+	base.Pages = u.pages
+	base.Password = u.password
+	return base
+}
+
+// close 关闭并删除临时文件
+func (u *upload) close() {
+	u.File.Close()
+	os.Remove(u.Name())
+}
+
+// receiveFile 接收元数据与数据块并写入临时文件，超过大小上限时立即结束；调用方负责调用 close
+func receiveFile(stream pdfReceiver, maxSize int64) (*upload, error) {
+	// 接受元数据
+	firstChunk, err := stream.Recv()
+	if err != nil {
+		logx.Errorf("接受元数据失败: %v", err)
+		return nil, err
+	}
+
+	meta := firstChunk.GetMetadate()
+	if meta == nil {
+		return nil, &rejectError{msg: "缺少元数据"}
+	}
+	layout, err := extractor.ParseLayout(meta.Layout)
+	if err != nil {
+		return nil, &rejectError{msg: err.Error()}
+	}
+	pages, err := extractor.ParsePageRanges(meta.Pages)
+	if err != nil {
+		return nil, &rejectError{msg: err.Error()}
+	}
+
+	// 文件格式在解析时按内容识别，MineType 仅作参考
+	// 创建临时文件
+	tmpFile, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		logx.Errorf("创建临时文件失败: %v", err)
+		return nil, err
+	}
+	fail := func(err error) (*upload, error) {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return nil, err
+	}
+
+	// 接受并写入数据块
+	var written int64
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logx.Errorf("接收数据失败: %v", err)
+			return fail(err)
+		}
+
+		if chunk := req.GetChunks(); chunk != nil {
+			written += int64(len(chunk))
+			if written > maxSize {
+				return fail(&rejectError{msg: fmt.Sprintf("文件超过大小限制（%d字节）", maxSize)})
+			}
+			if _, err := tmpFile.Write(chunk); err != nil {
+				logx.Errorf("写入临时文件失败: %v", err)
+				return fail(err)
+			}
+		}
+	}
+
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return fail(err)
+	}
+	return &upload{File: tmpFile, size: written, layout: layout, pages: pages, password: meta.Password}, nil
+}
+
+func asReject(err error) (string, bool) {
+	var reject *rejectError
+	if errors.As(err, &reject) {
+		return reject.msg, true
+	}
+	return "", false
+}
@@ -0,0 +1,202 @@
+package mcpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	headerSessionId       = "Mcp-Session-Id"
+	headerProtocolVersion = "Mcp-Protocol-Version"
+	shutdownTimeout       = 5 * time.Second
+)
+
+type HTTPOptions struct {
+	Path         string
+	AllowOrigins []string      // 为空时拒绝携带 Origin 头的请求
+	SessionTTL   time.Duration // 会话空闲过期时间
+	MaxBodyBytes int64
+	BearerToken  string // 非空时要求 Authorization: Bearer <token>
+}
+
+// HTTPServer streamable HTTP 传输：POST 请求直接返回 JSON 响应，不提供服务端推送流（GET 返回405）；
+// 实现 go-zero service.Service，可与 rpc 服务加入同一 ServiceGroup
+type HTTPServer struct {
+	server *Server
+	opts   HTTPOptions
+	srv    *http.Server
+
+	mu       sync.Mutex
+	sessions map[string]time.Time // 会话ID -> 最后活跃时间
+}
+
+func NewHTTPServer(server *Server, addr string, opts HTTPOptions) *HTTPServer {
+	h := &HTTPServer{
+		server:   server,
+		opts:     opts,
+		sessions: make(map[string]time.Time),
+	}
+	mux := http.NewServeMux()
+	mux.Handle(opts.Path, h)
+	h.srv = &http.Server{Addr: addr, Handler: mux}
+	return h
+}
+
+func (h *HTTPServer) Start() {
+	logx.Infof("Starting mcp http server at %s%s...", h.srv.Addr, h.opts.Path)
+	if err := h.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("mcp http server err: %v", err)
+	}
+}
+
+func (h *HTTPServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := h.srv.Shutdown(ctx); err != nil {
+		logx.Errorf("mcp http server shutdown err: %v", err)
+	}
+}
+
+func (h *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" && !slices.Contains(h.opts.AllowOrigins, origin) {
+		http.Error(w, "Forbidden: origin not allowed", http.StatusForbidden)
+		return
+	}
+	if v := r.Header.Get(headerProtocolVersion); v != "" && !slices.Contains(supportedVersions, v) {
+		http.Error(w, "Bad Request: unsupported protocol version", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodDelete:
+		sessionId := r.Header.Get(headerSessionId)
+		if !h.touch(sessionId) {
+			http.Error(w, "Not Found: session not found", http.StatusNotFound)
+			return
+		}
+		h.mu.Lock()
+		delete(h.sessions, sessionId)
+		h.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *HTTPServer) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.opts.MaxBodyBytes))
+	if err != nil {
+		http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	reqs, batch, errResp := decode(body)
+	if errResp != nil {
+		writeJSON(w, http.StatusBadRequest, encode(errResp))
+		return
+	}
+
+	// initialize 请求创建新会话，其余请求必须携带有效会话ID
+	initialize := slices.ContainsFunc(reqs, func(req *request) bool { return req.Method == "initialize" })
+	sessionId := r.Header.Get(headerSessionId)
+	switch {
+	case initialize && len(reqs) > 1:
+		writeJSON(w, http.StatusBadRequest, encode(errorResponse(nil, codeInvalidRequest, "initialize must not be batched")))
+		return
+	case initialize:
+		sessionId = h.newSession()
+	case sessionId == "":
+		http.Error(w, "Bad Request: missing "+headerSessionId, http.StatusBadRequest)
+		return
+	case !h.touch(sessionId):
+		http.Error(w, "Not Found: session not found", http.StatusNotFound)
+		return
+	}
+
+	resp := h.server.respond(r.Context(), reqs, batch)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set(headerSessionId, sessionId)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// authorized 未配置令牌时放行（仅允许监听本机地址，见 IsLoopback），否则以常量时间比较 Bearer 令牌
+func (h *HTTPServer) authorized(r *http.Request) bool {
+	if h.opts.BearerToken == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.opts.BearerToken)) == 1
+}
+
+// IsLoopback 监听地址是否仅限本机；主机为空（监听全部网卡）时返回false
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func writeJSON(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// newSession 创建会话，同时清理过期会话
+func (h *HTTPServer) newSession() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	id := hex.EncodeToString(buf)
+
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sid, lastSeen := range h.sessions {
+		if now.Sub(lastSeen) > h.opts.SessionTTL {
+			delete(h.sessions, sid)
+		}
+	}
+	h.sessions[id] = now
+	return id
+}
+
+// touch 会话有效时刷新活跃时间
+func (h *HTTPServer) touch(sessionId string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lastSeen, ok := h.sessions[sessionId]
+	if !ok || time.Since(lastSeen) > h.opts.SessionTTL {
+		delete(h.sessions, sessionId)
+		return false
+	}
+	h.sessions[sessionId] = time.Now()
+	return true
+}
@@ -0,0 +1,144 @@
+// Package mcpserver 实现 Model Context Protocol 服务端（JSON-RPC 2.0），提供 stdio 与 streamable HTTP 两种传输
+package mcpserver
+
+import "encoding/json"
+
+const (
+	jsonrpcVersion = "2.0"
+	latestVersion  = "2025-06-18"
+)
+
+// supportedVersions 支持的协议版本，客户端请求的版本不在其中时返回最新版本
+var supportedVersions = []string{latestVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC 错误码
+const (
+	codeParseError       = -32700
+	codeInvalidRequest   = -32600
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeInternalError    = -32603
+	codeResourceNotFound = -32002
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // 为空时是通知，不需要响应
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+func (r *request) isNotification() bool {
+	return len(r.ID) == 0
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error JSON-RPC 错误
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrInvalidParams 参数错误，工具与资源处理函数可直接返回
+func ErrInvalidParams(msg string) *Error {
+	return &Error{Code: codeInvalidParams, Message: msg}
+}
+
+// ErrResourceNotFound 资源不存在
+func ErrResourceNotFound(uri string) *Error {
+	return &Error{Code: codeResourceNotFound, Message: "Resource not found", Data: map[string]string{"uri": uri}}
+}
+
+type implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type initializeParams struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	ClientInfo      implementation `json:"clientInfo"`
+}
+
+type initializeResult struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ServerInfo      implementation `json:"serverInfo"`
+	Instructions    string         `json:"instructions,omitempty"`
+}
+
+// ToolInfo tools/list 中的工具描述
+type ToolInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema any    `json:"inputSchema"`
+}
+
+type callToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Content 工具结果内容，目前只使用文本
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ToolResult tools/call 结果，IsError 表示工具执行失败（而非协议错误），模型可据此调整调用
+type ToolResult struct {
+	Content           []Content `json:"content"`
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	IsError           bool      `json:"isError,omitempty"`
+}
+
+// TextResult 纯文本工具结果
+func TextResult(text string) *ToolResult {
+	return &ToolResult{Content: []Content{{Type: "text", Text: text}}}
+}
+
+// JSONResult 以JSON文本及结构化内容返回结果
+func JSONResult(v any) (*ToolResult, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{Content: []Content{{Type: "text", Text: string(data)}}, StructuredContent: v}, nil
+}
+
+// ResourceInfo resources/list 中的资源描述
+type ResourceInfo struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceTemplate resources/templates/list 中的资源模板
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents resources/read 返回的文本内容
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+type readResourceParams struct {
+	URI string `json:"uri"`
+}
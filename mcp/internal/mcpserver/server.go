@@ -0,0 +1,291 @@
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ToolHandler 工具处理函数；返回 *Error 时作为协议错误响应，其他错误作为 isError 结果返回给客户端
+type ToolHandler func(ctx context.Context, args json.RawMessage) (*ToolResult, error)
+
+type Tool struct {
+	Info    ToolInfo
+	Handler ToolHandler
+}
+
+// Resource 固定URI的资源
+type Resource struct {
+	Info ResourceInfo
+	Read func(ctx context.Context, uri string) (*ResourceContents, error)
+}
+
+// Template 以 Prefix 开头的一类资源，List 可选，用于在 resources/list 中列出具体资源
+type Template struct {
+	Info   ResourceTemplate
+	Prefix string
+	List   func(ctx context.Context) ([]ResourceInfo, error)
+	Read   func(ctx context.Context, uri string) (*ResourceContents, error)
+}
+
+type Server struct {
+	info         implementation
+	instructions string
+	tools        []Tool
+	resources    []Resource
+	templates    []Template
+}
+
+func NewServer(name, version, instructions string) *Server {
+	return &Server{
+		info:         implementation{Name: name, Version: version},
+		instructions: instructions,
+	}
+}
+
+func (s *Server) AddTool(t Tool) {
+	s.tools = append(s.tools, t)
+}
+
+func (s *Server) AddResource(r Resource) {
+	s.resources = append(s.resources, r)
+}
+
+func (s *Server) AddTemplate(t Template) {
+	s.templates = append(s.templates, t)
+}
+
+// Handle 处理一条消息（或批量消息），无需响应时返回nil
+func (s *Server) Handle(ctx context.Context, data []byte) []byte {
+	reqs, batch, errResp := decode(data)
+	if errResp != nil {
+		return encode(errResp)
+	}
+	return s.respond(ctx, reqs, batch)
+}
+
+func (s *Server) respond(ctx context.Context, reqs []*request, batch bool) []byte {
+	var resps []*response
+	for _, req := range reqs {
+		if resp := s.handleRequest(ctx, req); resp != nil {
+			resps = append(resps, resp)
+		}
+	}
+	switch {
+	case len(resps) == 0:
+		return nil
+	case batch:
+		return encode(resps)
+	default:
+		return encode(resps[0])
+	}
+}
+
+// decode 解析单条或批量消息；客户端发来的响应（没有method）会被忽略
+func decode(data []byte) ([]*request, bool, *response) {
+	data = bytes.TrimSpace(data)
+	batch := len(data) > 0 && data[0] == '['
+
+	var raws []json.RawMessage
+	if batch {
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return nil, true, errorResponse(nil, codeParseError, "Parse error")
+		}
+		if len(raws) == 0 {
+			return nil, true, errorResponse(nil, codeInvalidRequest, "Invalid Request")
+		}
+	} else {
+		raws = []json.RawMessage{data}
+	}
+
+	reqs := make([]*request, 0, len(raws))
+	for _, raw := range raws {
+		var req request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			if !batch {
+				return nil, false, errorResponse(nil, codeParseError, "Parse error")
+			}
+			req = request{} // 批量中的无效项按无效请求处理
+		}
+		reqs = append(reqs, &req)
+	}
+	return reqs, batch, nil
+}
+
+func encode(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logx.Errorf("encode mcp response failed: %v", err)
+		return nil
+	}
+	return data
+}
+
+func (s *Server) handleRequest(ctx context.Context, req *request) *response {
+	if req.Method == "" {
+		if req.isNotification() {
+			return errorResponse(nil, codeInvalidRequest, "Invalid Request")
+		}
+		return nil // 客户端对服务端请求的响应，本服务不发起请求
+	}
+	if req.JSONRPC != jsonrpcVersion {
+		return errorResponse(req.ID, codeInvalidRequest, "Invalid Request")
+	}
+
+	result, err := s.dispatch(ctx, req)
+	if req.isNotification() {
+		return nil
+	}
+	if err != nil {
+		var rpcErr *Error
+		if errors.As(err, &rpcErr) {
+			return &response{JSONRPC: jsonrpcVersion, ID: req.ID, Error: rpcErr}
+		}
+		logx.WithContext(ctx).Errorf("mcp %s failed: %v", req.Method, err)
+		return errorResponse(req.ID, codeInternalError, err.Error())
+	}
+	return &response{JSONRPC: jsonrpcVersion, ID: req.ID, Result: result}
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: jsonrpcVersion, ID: id, Error: &Error{Code: code, Message: msg}}
+}
+
+func (s *Server) dispatch(ctx context.Context, req *request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		tools := make([]ToolInfo, 0, len(s.tools))
+		for _, t := range s.tools {
+			tools = append(tools, t.Info)
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	case "resources/list":
+		return s.listResources(ctx)
+	case "resources/templates/list":
+		templates := make([]ResourceTemplate, 0, len(s.templates))
+		for _, t := range s.templates {
+			templates = append(templates, t.Info)
+		}
+		return map[string]any{"resourceTemplates": templates}, nil
+	case "resources/read":
+		return s.readResource(ctx, req.Params)
+	default:
+		if strings.HasPrefix(req.Method, "notifications/") {
+			return nil, nil // initialized、cancelled 等通知无需处理
+		}
+		return nil, &Error{Code: codeMethodNotFound, Message: "Method not found: " + req.Method}
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var p initializeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, ErrInvalidParams("invalid initialize params")
+	}
+
+	version := latestVersion
+	if slices.Contains(supportedVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	capabilities := map[string]any{}
+	if len(s.tools) > 0 {
+		capabilities["tools"] = map[string]any{}
+	}
+	if len(s.resources) > 0 || len(s.templates) > 0 {
+		capabilities["resources"] = map[string]any{}
+	}
+	logx.Infof("mcp client initialized: %s %s, protocol %s", p.ClientInfo.Name, p.ClientInfo.Version, version)
+
+	return &initializeResult{
+		ProtocolVersion: version,
+		Capabilities:    capabilities,
+		ServerInfo:      s.info,
+		Instructions:    s.instructions,
+	}, nil
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, error) {
+	var p callToolParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, ErrInvalidParams("invalid tools/call params")
+	}
+	idx := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Info.Name == p.Name })
+	if idx < 0 {
+		return nil, ErrInvalidParams("Unknown tool: " + p.Name)
+	}
+	if len(p.Arguments) == 0 {
+		p.Arguments = json.RawMessage("{}")
+	}
+
+	result, err := s.tools[idx].Handler(ctx, p.Arguments)
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return nil, rpcErr
+	}
+	if err != nil {
+		result = TextResult(err.Error())
+		result.IsError = true
+	}
+	return result, nil
+}
+
+func (s *Server) listResources(ctx context.Context) (any, error) {
+	resources := make([]ResourceInfo, 0, len(s.resources))
+	for _, r := range s.resources {
+		resources = append(resources, r.Info)
+	}
+	for _, t := range s.templates {
+		if t.List == nil {
+			continue
+		}
+		items, err := t.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", t.Info.Name, err)
+		}
+		resources = append(resources, items...)
+	}
+	return map[string]any{"resources": resources}, nil
+}
+
+func (s *Server) readResource(ctx context.Context, params json.RawMessage) (any, error) {
+	var p readResourceParams
+	if err := json.Unmarshal(params, &p); err != nil || p.URI == "" {
+		return nil, ErrInvalidParams("invalid resources/read params")
+	}
+
+	var read func(ctx context.Context, uri string) (*ResourceContents, error)
+	for _, r := range s.resources {
+		if r.Info.URI == p.URI {
+			read = r.Read
+		}
+	}
+	for _, t := range s.templates {
+		if read == nil && strings.HasPrefix(p.URI, t.Prefix) {
+			read = t.Read
+		}
+	}
+	if read == nil {
+		return nil, ErrResourceNotFound(p.URI)
+	}
+
+	contents, err := read(ctx, p.URI)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"contents": []*ResourceContents{contents}}, nil
+}
@@ -0,0 +1,39 @@
+package mcpserver
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+const maxStdioMessage = 64 << 20 // 单条消息上限，文档解析工具以base64传输文件
+
+// ServeStdio 从 in 逐行读取消息并将响应逐行写入 out，直到 in 关闭或 ctx 取消
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStdioMessage)
+	w := bufio.NewWriter(out)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		resp := s.Handle(ctx, line)
+		if resp == nil {
+			continue
+		}
+		// 消息以换行分隔，json.Marshal 的输出不含换行
+		if _, err := w.Write(append(resp, '\n')); err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
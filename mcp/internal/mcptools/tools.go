@@ -0,0 +1,357 @@
+// Package mcptools 将文档解析、知识库检索与题库查询注册为 MCP 工具和资源
+package mcptools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ai-gozero-agent/mcp/extractor"
+	"ai-gozero-agent/mcp/internal/mcpserver"
+	"ai-gozero-agent/mcp/internal/svc"
+	"ai-gozero-agent/mcp/knowledgefilter"
+)
+
+const (
+	topicsURI          = "questionbank://topics"
+	questionURIPrefix  = "questionbank://questions/"
+	maxListedQuestions = 100
+)
+
+// NewServer 创建 MCP 服务并注册工具与资源，未配置知识库时只提供文档解析工具
+func NewServer(svcCtx *svc.ServiceContext) *mcpserver.Server {
+	s := mcpserver.NewServer(svcCtx.Config.Name, "1.0.0",
+		"面试助手工具：解析简历或文档（PDF、Word、EPUB、HTML、Markdown、文本）、检索面试知识库、查询Go面试题库。")
+
+	s.AddTool(extractDocumentTool(svcCtx))
+	s.AddTool(extractPdfTool(svcCtx))
+	if svcCtx.Knowledge != nil {
+		s.AddTool(searchKnowledgeTool(svcCtx))
+		s.AddTool(searchQuestionsTool(svcCtx))
+		s.AddResource(topicsResource(svcCtx))
+		s.AddTemplate(questionTemplate(svcCtx))
+	}
+	return s
+}
+
+func decodeArgs(args json.RawMessage, v any) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return mcpserver.ErrInvalidParams("invalid arguments: " + err.Error())
+	}
+	return nil
+}
+
+func extractDocumentTool(svcCtx *svc.ServiceContext) mcpserver.Tool {
+	return mcpserver.Tool{
+		Info: mcpserver.ToolInfo{
+			Name:        "extract_document",
+			Description: "解析文档（如候选人简历）并返回文本内容，支持PDF、DOCX、EPUB、HTML、Markdown与纯文本，格式按内容自动识别",
+			InputSchema: extractInputSchema("base64编码的文件内容"),
+		},
+		Handler: extractHandler(svcCtx, ""),
+	}
+}
+
+// extractPdfTool 仅接受PDF，保留以兼容已有客户端
+func extractPdfTool(svcCtx *svc.ServiceContext) mcpserver.Tool {
+	return mcpserver.Tool{
+		Info: mcpserver.ToolInfo{
+			Name:        "extract_pdf",
+			Description: "解析PDF文件（如候选人简历）并返回文本内容",
+			InputSchema: extractInputSchema("base64编码的PDF文件内容"),
+		},
+		Handler: extractHandler(svcCtx, extractor.FormatPDF),
+	}
+}
+
+func extractInputSchema(dataDesc string) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"data":     map[string]any{"type": "string", "description": dataDesc},
+			"filename": map[string]any{"type": "string", "description": "可选，文件名"},
+			"layout": map[string]any{
+				"type":        "string",
+				"enum":        []string{string(extractor.LayoutText), string(extractor.LayoutMarkdown)},
+				"description": "输出版式，默认text；markdown 保留PDF中的标题、列表、表格与代码块",
+			},
+			"pages":    map[string]any{"type": "string", "description": "可选，只解析的PDF页码范围，如 1-3,5,8-"},
+			"password": map[string]any{"type": "string", "description": "可选，加密PDF的密码"},
+		},
+		"required": []string{"data"},
+	}
+}
+
+// extractHandler 解码文件并提取文本，only 非空时只接受该格式
+func extractHandler(svcCtx *svc.ServiceContext, only extractor.Format) mcpserver.ToolHandler {
+	maxSize := svcCtx.Config.MaxFileSize
+	return func(ctx context.Context, args json.RawMessage) (*mcpserver.ToolResult, error) {
+		var in struct {
+			Data     string `json:"data"`
+			Filename string `json:"filename"`
+			Layout   string `json:"layout"`
+			Pages    string `json:"pages"`
+			Password string `json:"password"`
+		}
+		if err := decodeArgs(args, &in); err != nil {
+			return nil, err
+		}
+		opts := svcCtx.Extract
+		layout, err := extractor.ParseLayout(in.Layout)
+		if err != nil {
+			return nil, mcpserver.ErrInvalidParams(err.Error())
+		}
+		pages, err := extractor.ParsePageRanges(in.Pages)
+		if err != nil {
+			return nil, mcpserver.ErrInvalidParams(err.Error())
+		}
+		opts.Layout, opts.Pages, opts.Password = layout, pages, in.Password
+		if int64(base64.StdEncoding.DecodedLen(len(in.Data))) > maxSize {
+			return nil, fmt.Errorf("文件超过大小限制（%d字节）", maxSize)
+		}
+		data, err := base64.StdEncoding.DecodeString(in.Data)
+		if err != nil {
+			return nil, mcpserver.ErrInvalidParams("data is not valid base64")
+		}
+
+		r := bytes.NewReader(data)
+		format, err := extractor.Detect(r, r.Size())
+		if err != nil {
+			return nil, err
+		}
+		if only != "" && format != only {
+			return nil, fmt.Errorf("仅支持%s文件", strings.ToUpper(string(only)))
+		}
+		doc, err := extractor.ExtractText(ctx, r, r.Size(), opts)
+		if errors.Is(err, extractor.ErrPassword) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("文档解析失败：%w", err)
+		}
+		return mcpserver.TextResult(infoNote(&doc.Info) + doc.Content + ocrNote(doc.OCRPages)), nil
+	}
+}
+
+// infoNote 文档标题、作者、页数与目录，没有标题、作者与目录时为空
+func infoNote(info *extractor.Info) string {
+	if info.Title == "" && info.Author == "" && len(info.Outline) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("[文档信息]\n")
+	if info.Title != "" {
+		sb.WriteString("标题：" + info.Title + "\n")
+	}
+	if info.Author != "" {
+		sb.WriteString("作者：" + info.Author + "\n")
+	}
+	fmt.Fprintf(&sb, "页数：%d\n", info.Pages)
+	if len(info.Outline) > 0 {
+		sb.WriteString("目录：\n")
+		writeOutline(&sb, info.Outline, 1)
+	}
+	sb.WriteString("[正文]\n")
+	return sb.String()
+}
+
+func writeOutline(sb *strings.Builder, items []*extractor.OutlineItem, depth int) {
+	for _, item := range items {
+		sb.WriteString(strings.Repeat("  ", depth-1) + "- " + item.Title)
+		if item.Page > 0 {
+			fmt.Fprintf(sb, "（第%d页）", item.Page)
+		}
+		sb.WriteByte('\n')
+		writeOutline(sb, item.Children, depth+1)
+	}
+}
+
+// ocrNote 标注由OCR识别的页面，提示模型文本可能存在识别错误
+func ocrNote(pages []extractor.OCRPage) string {
+	if len(pages) == 0 {
+		return ""
+	}
+	parts := make([]string, len(pages))
+	for i, p := range pages {
+		parts[i] = fmt.Sprintf("第%d页（置信度%.2f）", p.Page, p.Confidence)
+	}
+	return "[以下页面为扫描件，文本由OCR识别，可能存在识别错误：" + strings.Join(parts, "、") + "]"
+}
+
+func searchKnowledgeTool(svcCtx *svc.ServiceContext) mcpserver.Tool {
+	return mcpserver.Tool{
+		Info: mcpserver.ToolInfo{
+			Name:        "search_knowledge",
+			Description: "按语义检索面试知识库，返回最相关的知识片段",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{"type": "string", "description": "检索内容"},
+					"topK":  map[string]any{"type": "integer", "description": "返回片段数量，默认3", "minimum": 1, "maximum": 20},
+					"collections": map[string]any{
+						"type":        "array",
+						"items":       map[string]any{"type": "string"},
+						"description": "检索的知识库集合，如 go-core、distributed-systems；为空时检索全部集合",
+					},
+					"filter": map[string]any{
+						"type":        "string",
+						"description": "元数据过滤表达式，逗号分隔的条件同时满足，| 分隔的取值满足其一，如 topic=concurrency|gc, difficulty>=middle, tags=channel, language=zh, created_at>=2025-01-01",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		Handler: func(ctx context.Context, args json.RawMessage) (*mcpserver.ToolResult, error) {
+			var in struct {
+				Query       string   `json:"query"`
+				TopK        int      `json:"topK"`
+				Collections []string `json:"collections"`
+				Filter      string   `json:"filter"`
+			}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			if in.Query == "" {
+				return nil, mcpserver.ErrInvalidParams("query is required")
+			}
+			if in.TopK <= 0 || in.TopK > 20 {
+				in.TopK = 3
+			}
+
+			filter, err := knowledgefilter.Parse(in.Filter)
+			if err != nil {
+				return nil, mcpserver.ErrInvalidParams(err.Error())
+			}
+			filter = knowledgefilter.In("collection", in.Collections...).And(filter)
+
+			chunks, err := svcCtx.Knowledge.Search(ctx, in.Query, in.TopK, filter)
+			if err != nil {
+				return nil, err
+			}
+			return mcpserver.JSONResult(map[string]any{"results": chunks})
+		},
+	}
+}
+
+// searchQuestionsTool 按主题与难度查询题库，配置 Knowledge.ExposeAnswers 后才提供 includeAnswer 参数
+func searchQuestionsTool(svcCtx *svc.ServiceContext) mcpserver.Tool {
+	exposeAnswers := svcCtx.Config.Knowledge.ExposeAnswers
+	properties := map[string]any{
+		"topic":      map[string]any{"type": "string", "description": "主题，如 concurrency、memory、gc、basics；为空时不限"},
+		"difficulty": map[string]any{"type": "string", "enum": []string{"junior", "middle", "senior"}, "description": "难度，为空时不限"},
+		"limit":      map[string]any{"type": "integer", "description": "返回数量，默认10", "minimum": 1, "maximum": maxListedQuestions},
+	}
+	if exposeAnswers {
+		properties["includeAnswer"] = map[string]any{"type": "boolean", "description": "是否返回参考答案"}
+	}
+	return mcpserver.Tool{
+		Info: mcpserver.ToolInfo{
+			Name:        "search_questions",
+			Description: "按主题与难度查询面试题库",
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": properties,
+			},
+		},
+		Handler: func(ctx context.Context, args json.RawMessage) (*mcpserver.ToolResult, error) {
+			var in struct {
+				Topic         string `json:"topic"`
+				Difficulty    string `json:"difficulty"`
+				Limit         int    `json:"limit"`
+				IncludeAnswer bool   `json:"includeAnswer"`
+			}
+			if err := decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			if in.Limit <= 0 || in.Limit > maxListedQuestions {
+				in.Limit = 10
+			}
+
+			questions, err := svcCtx.Knowledge.Questions(ctx, in.Topic, in.Difficulty, in.Limit, exposeAnswers && in.IncludeAnswer)
+			if err != nil {
+				return nil, err
+			}
+			return mcpserver.JSONResult(map[string]any{"questions": questions})
+		},
+	}
+}
+
+func topicsResource(svcCtx *svc.ServiceContext) mcpserver.Resource {
+	return mcpserver.Resource{
+		Info: mcpserver.ResourceInfo{
+			URI:         topicsURI,
+			Name:        "题库主题",
+			Description: "题库中的主题及各主题题目数量",
+			MimeType:    "application/json",
+		},
+		Read: func(ctx context.Context, uri string) (*mcpserver.ResourceContents, error) {
+			topics, err := svcCtx.Knowledge.Topics(ctx)
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(map[string]any{"topics": topics})
+			if err != nil {
+				return nil, err
+			}
+			return &mcpserver.ResourceContents{URI: uri, MimeType: "application/json", Text: string(data)}, nil
+		},
+	}
+}
+
+// questionTemplate 题目资源，配置 Knowledge.ExposeAnswers 后才包含参考答案
+func questionTemplate(svcCtx *svc.ServiceContext) mcpserver.Template {
+	exposeAnswers := svcCtx.Config.Knowledge.ExposeAnswers
+	description := "题库中的单道题目"
+	if exposeAnswers {
+		description += "及参考答案"
+	}
+	return mcpserver.Template{
+		Info: mcpserver.ResourceTemplate{
+			URITemplate: questionURIPrefix + "{id}",
+			Name:        "面试题",
+			Description: description,
+			MimeType:    "text/markdown",
+		},
+		Prefix: questionURIPrefix,
+		List: func(ctx context.Context) ([]mcpserver.ResourceInfo, error) {
+			questions, err := svcCtx.Knowledge.Questions(ctx, "", "", maxListedQuestions, false)
+			if err != nil {
+				return nil, err
+			}
+			resources := make([]mcpserver.ResourceInfo, 0, len(questions))
+			for _, q := range questions {
+				resources = append(resources, mcpserver.ResourceInfo{
+					URI:         questionURIPrefix + strconv.FormatInt(q.ID, 10),
+					Name:        fmt.Sprintf("%s/%s #%d", q.Topic, q.Difficulty, q.ID),
+					Description: q.Content,
+					MimeType:    "text/markdown",
+				})
+			}
+			return resources, nil
+		},
+		Read: func(ctx context.Context, uri string) (*mcpserver.ResourceContents, error) {
+			id, err := strconv.ParseInt(strings.TrimPrefix(uri, questionURIPrefix), 10, 64)
+			if err != nil {
+				return nil, mcpserver.ErrResourceNotFound(uri)
+			}
+			q, err := svcCtx.Knowledge.Question(ctx, id)
+			if errors.Is(err, svc.ErrNotFound) {
+				return nil, mcpserver.ErrResourceNotFound(uri)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			text := fmt.Sprintf("# %s\n\n- 主题：%s\n- 难度：%s\n", q.Content, q.Topic, q.Difficulty)
+			if exposeAnswers {
+				text += fmt.Sprintf("\n## 参考答案\n\n%s\n", q.ReferenceAnswer)
+			}
+			return &mcpserver.ResourceContents{URI: uri, MimeType: "text/markdown", Text: text}, nil
+		},
+	}
+}
@@ -21,8 +21,14 @@ func NewPdfProcessorServer(svcCtx *svc.ServiceContext) *PdfProcessorServer {
 	}
 }
 
-// 流式上传PDF并返回解析文本
+// 流式上传文档并返回解析文本
 func (s *PdfProcessorServer) ExtractText(stream mcp.PdfProcessor_ExtractTextServer) error {
 	l := logic.NewExtractTextLogic(stream.Context(), s.svcCtx)
 	return l.ExtractText(stream)
 }
+
+// 流式上传文档，解析过程中逐页返回文本与进度（EPUB按章节，其余格式整篇为一页）
+func (s *PdfProcessorServer) ExtractTextStream(stream mcp.PdfProcessor_ExtractTextStreamServer) error {
+	l := logic.NewExtractTextStreamLogic(stream.Context(), s.svcCtx)
+	return l.ExtractTextStream(stream)
+}
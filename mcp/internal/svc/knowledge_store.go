@@ -0,0 +1,170 @@
+package svc
+
+import (
+	"ai-gozero-agent/mcp/internal/config"
+	"ai-gozero-agent/mcp/knowledgefilter"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrNotFound 记录不存在
+var ErrNotFound = errors.New("not found")
+
+type KnowledgeChunk struct {
+	ID         int64     `json:"id"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	Collection string    `json:"collection"`
+	Topic      string    `json:"topic,omitempty"`
+	Difficulty string    `json:"difficulty,omitempty"`
+	Language   string    `json:"language,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type Question struct {
+	ID              int64  `json:"id"`
+	Topic           string `json:"topic"`
+	Difficulty      string `json:"difficulty"`
+	Content         string `json:"content"`
+	ReferenceAnswer string `json:"referenceAnswer,omitempty"`
+}
+
+type TopicCount struct {
+	Topic string `json:"topic"`
+	Count int64  `json:"count"`
+}
+
+// KnowledgeStore 只读访问api服务写入的知识库与题库
+type KnowledgeStore struct {
+	pool           *pgxpool.Pool
+	client         *openai.Client
+	embeddingModel string
+}
+
+func NewKnowledgeStore(c config.KnowledgeConfig) (*KnowledgeStore, error) {
+	connString := fmt.Sprintf("postgres://%s:%s@%s:%d/%s", c.User, c.Password, c.Host, c.Port, c.DBName)
+	poolConfig, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.MaxConns = int32(c.MaxConn)
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	openaiConf := openai.DefaultConfig(c.ApiKey)
+	if c.BaseURL != "" {
+		openaiConf.BaseURL = c.BaseURL
+	}
+	return &KnowledgeStore{
+		pool:           pool,
+		client:         openai.NewClientWithConfig(openaiConf),
+		embeddingModel: c.EmbeddingModel,
+	}, nil
+}
+
+// Search 按向量相似度检索满足 filter 的知识块，filter 为nil时检索全部
+func (s *KnowledgeStore) Search(ctx context.Context, query string, topK int, filter *knowledgefilter.Filter) ([]KnowledgeChunk, error) {
+	resp, err := s.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+		Input: []string{query},
+		Model: openai.EmbeddingModel(s.embeddingModel),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create embedding: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.New("未返回嵌入数据")
+	}
+	embeddingJson, err := json.Marshal(resp.Data[0].Embedding)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := filter.Where(3)
+	sql := `SELECT id, title, content, collection, topic, difficulty, language, source, tags, created_at FROM knowledge_base
+		WHERE ` + where + ` ORDER BY embedding::jsonb::text <-> $1::text LIMIT $2`
+	rows, err := s.pool.Query(ctx, sql, append([]any{embeddingJson, topK}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("DB select knowledge: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []KnowledgeChunk
+	for rows.Next() {
+		var c KnowledgeChunk
+		if err := rows.Scan(&c.ID, &c.Title, &c.Content, &c.Collection, &c.Topic, &c.Difficulty, &c.Language, &c.Source, &c.Tags, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks, rows.Err()
+}
+
+// Questions 查询题库，topic、difficulty 为空时不限制；withAnswer 为false时不返回参考答案
+func (s *KnowledgeStore) Questions(ctx context.Context, topic, difficulty string, limit int, withAnswer bool) ([]Question, error) {
+	sql := `SELECT id, topic, difficulty, content, reference_answer FROM question_bank
+		WHERE ($1 = '' OR topic = $1) AND ($2 = '' OR difficulty = $2)
+		ORDER BY id LIMIT $3`
+	rows, err := s.pool.Query(ctx, sql, topic, difficulty, limit)
+	if err != nil {
+		return nil, fmt.Errorf("DB select questions: %w", err)
+	}
+	defer rows.Close()
+
+	var questions []Question
+	for rows.Next() {
+		var q Question
+		if err := rows.Scan(&q.ID, &q.Topic, &q.Difficulty, &q.Content, &q.ReferenceAnswer); err != nil {
+			return nil, err
+		}
+		if !withAnswer {
+			q.ReferenceAnswer = ""
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+// Question 按ID查询题目（含参考答案）
+func (s *KnowledgeStore) Question(ctx context.Context, id int64) (*Question, error) {
+	sql := `SELECT id, topic, difficulty, content, reference_answer FROM question_bank WHERE id = $1`
+	var q Question
+	err := s.pool.QueryRow(ctx, sql, id).Scan(&q.ID, &q.Topic, &q.Difficulty, &q.Content, &q.ReferenceAnswer)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DB select question: %w", err)
+	}
+	return &q, nil
+}
+
+// Topics 题库各主题题目数量
+func (s *KnowledgeStore) Topics(ctx context.Context) ([]TopicCount, error) {
+	rows, err := s.pool.Query(ctx, `SELECT topic, COUNT(*) FROM question_bank GROUP BY topic ORDER BY topic`)
+	if err != nil {
+		return nil, fmt.Errorf("DB select topics: %w", err)
+	}
+	defer rows.Close()
+
+	var topics []TopicCount
+	for rows.Next() {
+		var t TopicCount
+		if err := rows.Scan(&t.Topic, &t.Count); err != nil {
+			return nil, err
+		}
+		topics = append(topics, t)
+	}
+	return topics, rows.Err()
+}
@@ -0,0 +1,21 @@
+package svc
+
+import (
+	"ai-gozero-agent/mcp/extractor"
+	"ai-gozero-agent/mcp/internal/config"
+	"log"
+)
+
+// newOCR 按配置创建OCR引擎，Engine 为 none 时返回nil
+func newOCR(c config.OCRConfig) extractor.OCR {
+	switch c.Engine {
+	case "tesseract":
+		return extractor.NewTesseract(c.TesseractPath, c.Lang, c.Timeout)
+	case "http":
+		if c.URL == "" {
+			log.Fatalf("OCR.URL is required for http engine")
+		}
+		return extractor.NewHTTPOCR(c.URL, c.Headers, c.Timeout)
+	}
+	return nil
+}
@@ -1,21 +1,39 @@
 package svc
 
 import (
+	"ai-gozero-agent/mcp/extractor"
 	"ai-gozero-agent/mcp/internal/config"
-	"fmt"
 	"github.com/unidoc/unipdf/v3/common/license"
+	"github.com/zeromicro/go-zero/core/logx"
+	"log"
 )
 
 type ServiceContext struct {
-	Config config.Config
+	Config    config.Config
+	Knowledge *KnowledgeStore   // 知识库与题库，未配置时为nil
+	Extract   extractor.Options // 文档解析选项（OCR）
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
 	err := license.SetMeteredKey(c.UniPDFLicense)
 	if err != nil {
-		fmt.Printf("license metered key error: %v\n", err)
+		// stdio 模式下标准输出用于协议消息，不能直接打印
+		logx.Errorf("license metered key error: %v", err)
+	}
+
+	var knowledge *KnowledgeStore
+	if c.Knowledge.Host != "" {
+		knowledge, err = NewKnowledgeStore(c.Knowledge)
+		if err != nil {
+			log.Fatalf("NewKnowledgeStore err: %v", err)
+		}
 	}
 	return &ServiceContext{
-		Config: c,
+		Config:    c,
+		Knowledge: knowledge,
+		Extract: extractor.Options{
+			OCR:         newOCR(c.OCR),
+			OCRMinChars: c.OCR.MinChars,
+		},
 	}
 }
@@ -1,49 +0,0 @@
-package utils
-
-import (
-	"bytes"
-	"github.com/unidoc/unipdf/v3/extractor"
-	"github.com/unidoc/unipdf/v3/model"
-	"io"
-	"strings"
-)
-
-func ExtractPDFText(file io.Reader) (string, error) {
-	buf := bytes.NewBuffer(nil)
-	if _, err := io.Copy(buf, file); err != nil {
-		return "", err
-	}
-
-	pdfReader, err := model.NewPdfReader(bytes.NewReader(buf.Bytes()))
-	if err != nil {
-		return "", err
-	}
-
-	var textBuilder strings.Builder
-	numPages, err := pdfReader.GetNumPages()
-	if err != nil {
-		return "", err
-	}
-
-	for i := 1; i <= numPages; i++ {
-		page, err := pdfReader.GetPage(i)
-		if err != nil {
-			return "", err
-		}
-
-		ex, err := extractor.New(page)
-		if err != nil {
-			return "", err
-		}
-
-		pageText, err := ex.ExtractText()
-		if err != nil {
-			return "", err
-		}
-
-		textBuilder.WriteString(pageText)
-		textBuilder.WriteString("\n\n")
-	}
-
-	return textBuilder.String(), nil
-}
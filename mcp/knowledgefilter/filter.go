@@ -0,0 +1,280 @@
+// Package knowledgefilter 解析知识块元数据过滤表达式并生成SQL条件，api与mcp服务的知识检索共用。
+//
+// 表达式由逗号分隔的条件组成，条件之间为“且”，同一条件中以 | 分隔的多个取值为“或”：
+//
+//	topic=concurrency|gc, difficulty>=middle, tags=channel, language=zh, source=runtime/*, created_at>=2025-01-01
+//
+// 文本字段（collection、topic、language、source、title）支持 = 与 !=，取值以 * 结尾时按前缀匹配；
+// tags 的 = 表示包含任一标签；difficulty 按 junior < middle < senior 还支持比较；
+// created_at 只支持比较，取值为日期（2006-01-02）或 RFC3339 时间。取值包含逗号或 | 时用双引号括起
+package knowledgefilter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Difficulties 难度，按从低到高排列，与题库一致
+var Difficulties = []string{"junior", "middle", "senior"}
+
+type kind int
+
+const (
+	kindText kind = iota
+	kindTags
+	kindDifficulty
+	kindTime
+)
+
+// fields 可过滤的字段，键同时为 knowledge_base 表的列名
+var fields = map[string]kind{
+	"collection": kindText,
+	"topic":      kindText,
+	"language":   kindText,
+	"source":     kindText,
+	"title":      kindText,
+	"tags":       kindTags,
+	"difficulty": kindDifficulty,
+	"created_at": kindTime,
+}
+
+// lowered 入库时统一为小写的字段，取值同样转为小写
+var lowered = []string{"topic", "difficulty", "language"}
+
+// ops 按长度从长到短排列，先匹配 >= 再匹配 >
+var ops = []string{">=", "<=", "!=", "=", ">", "<"}
+
+// Filter 过滤条件，nil 表示不过滤
+type Filter struct {
+	conds []cond
+}
+
+type cond struct {
+	field  string
+	op     string
+	values []string
+	time   time.Time // created_at 的取值
+}
+
+// Parse 解析过滤表达式，空表达式返回nil
+func Parse(expr string) (*Filter, error) {
+	terms, err := split(expr, ',')
+	if err != nil {
+		return nil, err
+	}
+	var f Filter
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term == "" {
+			continue
+		}
+		c, err := parseCond(term)
+		if err != nil {
+			return nil, err
+		}
+		f.conds = append(f.conds, c)
+	}
+	if len(f.conds) == 0 {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+func parseCond(term string) (cond, error) {
+	i := strings.IndexAny(term, "=!<>")
+	if i <= 0 {
+		return cond{}, fmt.Errorf("无效的过滤条件: %s", term)
+	}
+	c := cond{field: strings.ToLower(strings.TrimSpace(term[:i]))}
+	for _, op := range ops {
+		if strings.HasPrefix(term[i:], op) {
+			c.op = op
+			break
+		}
+	}
+	k, ok := fields[c.field]
+	if !ok {
+		return cond{}, fmt.Errorf("不支持过滤字段 %s", c.field)
+	}
+	if c.op == "" {
+		return cond{}, fmt.Errorf("无效的过滤条件: %s", term)
+	}
+	ordered := c.op != "=" && c.op != "!="
+	if ordered && k != kindDifficulty && k != kindTime {
+		return cond{}, fmt.Errorf("字段 %s 不支持 %s", c.field, c.op)
+	}
+	if !ordered && k == kindTime {
+		return cond{}, fmt.Errorf("字段 %s 只支持 >、>=、<、<=", c.field)
+	}
+
+	values, err := split(term[i+len(c.op):], '|')
+	if err != nil {
+		return cond{}, err
+	}
+	for _, v := range values {
+		if v = unquote(strings.TrimSpace(v)); v == "" {
+			return cond{}, fmt.Errorf("过滤条件缺少取值: %s", term)
+		}
+		if slices.Contains(lowered, c.field) {
+			v = strings.ToLower(v)
+		}
+		c.values = append(c.values, v)
+	}
+	if ordered && len(c.values) > 1 {
+		return cond{}, fmt.Errorf("%s 只能有一个取值: %s", c.op, term)
+	}
+
+	switch k {
+	case kindDifficulty:
+		for _, v := range c.values {
+			if !slices.Contains(Difficulties, v) {
+				return cond{}, fmt.Errorf("难度只能为 %s: %s", strings.Join(Difficulties, "、"), v)
+			}
+		}
+	case kindTime:
+		if c.time, err = parseTime(c.values[0]); err != nil {
+			return cond{}, err
+		}
+	case kindTags:
+		for _, v := range c.values {
+			if strings.HasSuffix(v, "*") {
+				return cond{}, fmt.Errorf("tags 不支持前缀匹配: %s", v)
+			}
+		}
+	}
+	return c, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("无效的时间 %s，应为 2006-01-02 或 RFC3339", s)
+}
+
+// split 按分隔符切分，忽略双引号内的分隔符
+func split(s string, sep rune) ([]string, error) {
+	var parts []string
+	var b strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			parts = append(parts, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteRune(r)
+	}
+	if quoted {
+		return nil, errors.New("过滤表达式中的双引号未闭合")
+	}
+	return append(parts, b.String()), nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// In 字段取值为 values 之一，values 为空时返回nil
+func In(field string, values ...string) *Filter {
+	if len(values) == 0 {
+		return nil
+	}
+	return &Filter{conds: []cond{{field: field, op: "=", values: values}}}
+}
+
+// And 同时满足两组条件，任一为nil时返回另一个
+func (f *Filter) And(other *Filter) *Filter {
+	if f == nil {
+		return other
+	}
+	if other == nil {
+		return f
+	}
+	return &Filter{conds: slices.Concat(f.conds, other.conds)}
+}
+
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	terms := make([]string, len(f.conds))
+	for i, c := range f.conds {
+		values := make([]string, len(c.values))
+		for j, v := range c.values {
+			if strings.ContainsAny(v, ",|") {
+				v = `"` + v + `"`
+			}
+			values[j] = v
+		}
+		terms[i] = c.field + c.op + strings.Join(values, "|")
+	}
+	return strings.Join(terms, ", ")
+}
+
+// Where 生成SQL条件（不含WHERE）与参数，占位符从 $start 开始编号；没有条件时返回 TRUE
+func (f *Filter) Where(start int) (string, []any) {
+	if f == nil || len(f.conds) == 0 {
+		return "TRUE", nil
+	}
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(start+len(args)-1)
+	}
+
+	clauses := make([]string, len(f.conds))
+	for i, c := range f.conds {
+		var clause string
+		switch fields[c.field] {
+		case kindTags:
+			clause = fmt.Sprintf("tags && %s::text[]", arg(c.values))
+		case kindTime:
+			clause = fmt.Sprintf("created_at %s %s", c.op, arg(c.time))
+		case kindDifficulty:
+			if c.op != "=" && c.op != "!=" {
+				clause = fmt.Sprintf("array_position(%s::text[], difficulty) %s %s",
+					arg(Difficulties), c.op, arg(slices.Index(Difficulties, c.values[0])+1))
+				break
+			}
+			fallthrough
+		default:
+			var exact []string
+			var alts []string
+			for _, v := range c.values {
+				if prefix, ok := strings.CutSuffix(v, "*"); ok {
+					alts = append(alts, fmt.Sprintf("%s LIKE %s", c.field, arg(escapeLike(prefix)+"%")))
+				} else {
+					exact = append(exact, v)
+				}
+			}
+			if len(exact) > 0 {
+				alts = append(alts, fmt.Sprintf("%s = ANY(%s::text[])", c.field, arg(exact)))
+			}
+			clause = strings.Join(alts, " OR ")
+		}
+		if c.op == "!=" {
+			clause = "NOT (" + clause + ")"
+		} else {
+			clause = "(" + clause + ")"
+		}
+		clauses[i] = clause
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
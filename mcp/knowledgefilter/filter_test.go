@@ -0,0 +1,214 @@
+package knowledgefilter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWhere(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name  string
+		expr  string
+		start int
+		where string
+		args  []any
+	}{
+		{
+			name:  "empty",
+			expr:  " , ",
+			start: 1,
+			where: "TRUE",
+		},
+		{
+			name:  "alternatives",
+			expr:  "topic=Concurrency|gc",
+			start: 1,
+			where: "(topic = ANY($1::text[]))",
+			args:  []any{[]string{"concurrency", "gc"}},
+		},
+		{
+			name:  "quoted value with separators",
+			expr:  `title="a, b|c"`,
+			start: 1,
+			where: "(title = ANY($1::text[]))",
+			args:  []any{[]string{"a, b|c"}},
+		},
+		{
+			name:  "not equal",
+			expr:  "language!=en",
+			start: 1,
+			where: "NOT (language = ANY($1::text[]))",
+			args:  []any{[]string{"en"}},
+		},
+		{
+			name:  "prefix",
+			expr:  "source=runtime/*",
+			start: 1,
+			where: "(source LIKE $1)",
+			args:  []any{"runtime/%"},
+		},
+		{
+			name:  "prefix and exact",
+			expr:  "source=runtime/*|sync/atomic",
+			start: 1,
+			where: "(source LIKE $1 OR source = ANY($2::text[]))",
+			args:  []any{"runtime/%", []string{"sync/atomic"}},
+		},
+		{
+			name:  "not prefix",
+			expr:  "source!=runtime/*",
+			start: 1,
+			where: "NOT (source LIKE $1)",
+			args:  []any{"runtime/%"},
+		},
+		{
+			name:  "prefix escapes like wildcards",
+			expr:  "source=100%_done*",
+			start: 1,
+			where: "(source LIKE $1)",
+			args:  []any{`100\%\_done%`},
+		},
+		{
+			name:  "tags",
+			expr:  "tags=channel|gc",
+			start: 1,
+			where: "(tags && $1::text[])",
+			args:  []any{[]string{"channel", "gc"}},
+		},
+		{
+			name:  "difficulty at least",
+			expr:  "difficulty>=Middle",
+			start: 1,
+			where: "(array_position($1::text[], difficulty) >= $2)",
+			args:  []any{Difficulties, 2},
+		},
+		{
+			name:  "difficulty below",
+			expr:  "difficulty<senior",
+			start: 1,
+			where: "(array_position($1::text[], difficulty) < $2)",
+			args:  []any{Difficulties, 3},
+		},
+		{
+			name:  "difficulty not equal",
+			expr:  "difficulty!=junior",
+			start: 1,
+			where: "NOT (difficulty = ANY($1::text[]))",
+			args:  []any{[]string{"junior"}},
+		},
+		{
+			name:  "created_at date",
+			expr:  "created_at>=2025-01-01",
+			start: 1,
+			where: "(created_at >= $1)",
+			args:  []any{day},
+		},
+		{
+			name:  "created_at rfc3339",
+			expr:  "created_at<2025-01-02T03:04:05Z",
+			start: 1,
+			where: "(created_at < $1)",
+			args:  []any{time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+		{
+			name:  "conditions numbered from start",
+			expr:  "topic=gc, difficulty>junior, created_at<=2025-01-01",
+			start: 3,
+			where: "(topic = ANY($3::text[])) AND (array_position($4::text[], difficulty) > $5) AND (created_at <= $6)",
+			args:  []any{[]string{"gc"}, Difficulties, 1, day},
+		},
+		{
+			name:  "prefix and tags numbered from start",
+			expr:  "source!=runtime/*|sync/atomic, tags=gc",
+			start: 2,
+			where: "NOT (source LIKE $2 OR source = ANY($3::text[])) AND (tags && $4::text[])",
+			args:  []any{"runtime/%", []string{"sync/atomic"}, []string{"gc"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.expr, err)
+			}
+			where, args := f.Where(tt.start)
+			if where != tt.where {
+				t.Errorf("where = %q, want %q", where, tt.where)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"unknown field", "author=rob"},
+		{"missing field", "=gc"},
+		{"missing operator", "topic"},
+		{"missing value", "topic="},
+		{"ordered text field", "topic>gc"},
+		{"created_at equality", "created_at=2025-01-01"},
+		{"invalid time", "created_at>yesterday"},
+		{"unknown difficulty", "difficulty=expert"},
+		{"ordered with alternatives", "difficulty>=junior|middle"},
+		{"tags prefix", "tags=go*"},
+		{"unterminated quote", `title="abc`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if f, err := Parse(tt.expr); err == nil {
+				t.Errorf("Parse(%q) = %v, want error", tt.expr, f)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", ""},
+		{"Topic = GC|memory ,tags=channel", "topic=gc|memory, tags=channel"},
+		{`title="a, b|c"`, `title="a, b|c"`},
+		{"difficulty>=middle, source!=runtime/*", "difficulty>=middle, source!=runtime/*"},
+	}
+	for _, tt := range tests {
+		f, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.expr, err)
+		}
+		if got := f.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.expr, got, tt.want)
+		}
+		if _, err := Parse(f.String()); err != nil {
+			t.Errorf("Parse(%q) round trip error: %v", f.String(), err)
+		}
+	}
+}
+
+func TestInAnd(t *testing.T) {
+	if f := In("collection"); f != nil {
+		t.Errorf("In without values = %v, want nil", f)
+	}
+	extra, err := Parse("topic=gc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := In("collection", "go-core", "default").And(nil).And(extra)
+	where, args := f.Where(1)
+	want := "(collection = ANY($1::text[])) AND (topic = ANY($2::text[]))"
+	if where != want {
+		t.Errorf("where = %q, want %q", where, want)
+	}
+	if !reflect.DeepEqual(args, []any{[]string{"go-core", "default"}, []string{"gc"}}) {
+		t.Errorf("args = %#v", args)
+	}
+}
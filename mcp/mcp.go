@@ -1,29 +1,54 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
 
 	"ai-gozero-agent/mcp/internal/config"
+	"ai-gozero-agent/mcp/internal/mcpserver"
+	"ai-gozero-agent/mcp/internal/mcptools"
 	"ai-gozero-agent/mcp/internal/server"
 	"ai-gozero-agent/mcp/internal/svc"
 	"ai-gozero-agent/mcp/types/mcp"
 
 	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/core/service"
 	"github.com/zeromicro/go-zero/zrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
-var configFile = flag.String("f", "etc/mcp.yaml", "the config file")
+var (
+	configFile = flag.String("f", "etc/mcp.yaml", "the config file")
+	stdio      = flag.Bool("stdio", false, "serve Model Context Protocol over stdin/stdout instead of rpc")
+)
 
 func main() {
 	flag.Parse()
 
 	var c config.Config
 	conf.MustLoad(*configFile, &c)
+
+	// stdio 模式下标准输出只能写协议消息，日志改写到标准错误
+	if *stdio {
+		logx.SetWriter(logx.NewWriter(os.Stderr))
+	}
 	ctx := svc.NewServiceContext(c)
+	mcpServer := mcptools.NewServer(ctx)
+
+	if *stdio {
+		if err := mcpServer.ServeStdio(context.Background(), os.Stdin, os.Stdout); err != nil {
+			logx.Errorf("mcp stdio server err: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	group := service.NewServiceGroup()
+	defer group.Stop()
 
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		mcp.RegisterPdfProcessorServer(grpcServer, server.NewPdfProcessorServer(ctx))
@@ -32,8 +57,24 @@ func main() {
 			reflection.Register(grpcServer)
 		}
 	})
-	defer s.Stop()
+	group.Add(s)
+
+	// 监听非本机地址时必须配置令牌，否则文档解析、知识检索与题库会对网络中的任何人开放
+	switch {
+	case c.MCPServer.ListenOn == "":
+	case c.MCPServer.BearerToken == "" && !mcpserver.IsLoopback(c.MCPServer.ListenOn):
+		logx.Errorf("MCPServer.ListenOn %s is not a loopback address and MCPServer.BearerToken is empty, mcp http transport not started",
+			c.MCPServer.ListenOn)
+	default:
+		group.Add(mcpserver.NewHTTPServer(mcpServer, c.MCPServer.ListenOn, mcpserver.HTTPOptions{
+			Path:         c.MCPServer.Path,
+			AllowOrigins: c.MCPServer.AllowOrigins,
+			SessionTTL:   c.MCPServer.SessionTTL,
+			BearerToken:  c.MCPServer.BearerToken,
+			MaxBodyBytes: c.MaxFileSize*4/3 + 64*1024, // base64 编码后的文件及其余参数
+		}))
+	}
 
 	fmt.Printf("Starting rpc server at %s...\n", c.ListenOn)
-	s.Start()
+	group.Start()
 }
@@ -14,13 +14,19 @@ import (
 )
 
 type (
-	Metadata    = mcp.Metadata
-	PdfRequest  = mcp.PdfRequest
-	PdfResponse = mcp.PdfResponse
+	DocumentInfo = mcp.DocumentInfo
+	ExtractEvent = mcp.ExtractEvent
+	Metadata     = mcp.Metadata
+	OcrPage      = mcp.OcrPage
+	OutlineItem  = mcp.OutlineItem
+	PdfRequest   = mcp.PdfRequest
+	PdfResponse  = mcp.PdfResponse
 
 	PdfProcessor interface {
-		// 流式上传PDF并返回解析文本
+		// 流式上传文档并返回解析文本
 		ExtractText(ctx context.Context, opts ...grpc.CallOption) (mcp.PdfProcessor_ExtractTextClient, error)
+		// 流式上传文档，解析过程中逐页返回文本与进度（EPUB按章节，其余格式整篇为一页）
+		ExtractTextStream(ctx context.Context, opts ...grpc.CallOption) (mcp.PdfProcessor_ExtractTextStreamClient, error)
 	}
 
 	defaultPdfProcessor struct {
@@ -34,8 +40,14 @@ func NewPdfProcessor(cli zrpc.Client) PdfProcessor {
 	}
 }
 
-// 流式上传PDF并返回解析文本
+// 流式上传文档并返回解析文本
 func (m *defaultPdfProcessor) ExtractText(ctx context.Context, opts ...grpc.CallOption) (mcp.PdfProcessor_ExtractTextClient, error) {
 	client := mcp.NewPdfProcessorClient(m.cli.Conn())
 	return client.ExtractText(ctx, opts...)
 }
+
+// 流式上传文档，解析过程中逐页返回文本与进度（EPUB按章节，其余格式整篇为一页）
+func (m *defaultPdfProcessor) ExtractTextStream(ctx context.Context, opts ...grpc.CallOption) (mcp.PdfProcessor_ExtractTextStreamClient, error) {
+	client := mcp.NewPdfProcessorClient(m.cli.Conn())
+	return client.ExtractTextStream(ctx, opts...)
+}
@@ -19,17 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PdfProcessor_ExtractText_FullMethodName = "/mcp.PdfProcessor/ExtractText"
+	PdfProcessor_ExtractText_FullMethodName       = "/mcp.PdfProcessor/ExtractText"
+	PdfProcessor_ExtractTextStream_FullMethodName = "/mcp.PdfProcessor/ExtractTextStream"
 )
 
 // PdfProcessorClient is the client API for PdfProcessor service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
-// PDF处理服务定义
+// 文档处理服务定义，支持PDF、DOCX、EPUB、HTML、Markdown与纯文本，格式按内容识别
 type PdfProcessorClient interface {
-	// 流式上传PDF并返回解析文本
+	// 流式上传文档并返回解析文本
 	ExtractText(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PdfRequest, PdfResponse], error)
+	// 流式上传文档，解析过程中逐页返回文本与进度（EPUB按章节，其余格式整篇为一页）
+	ExtractTextStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PdfRequest, ExtractEvent], error)
 }
 
 type pdfProcessorClient struct {
@@ -53,14 +56,29 @@ func (c *pdfProcessorClient) ExtractText(ctx context.Context, opts ...grpc.CallO
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PdfProcessor_ExtractTextClient = grpc.ClientStreamingClient[PdfRequest, PdfResponse]
 
+func (c *pdfProcessorClient) ExtractTextStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PdfRequest, ExtractEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PdfProcessor_ServiceDesc.Streams[1], PdfProcessor_ExtractTextStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PdfRequest, ExtractEvent]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfProcessor_ExtractTextStreamClient = grpc.BidiStreamingClient[PdfRequest, ExtractEvent]
+
 // PdfProcessorServer is the server API for PdfProcessor service.
 // All implementations must embed UnimplementedPdfProcessorServer
 // for forward compatibility.
 //
-// PDF处理服务定义
+// 文档处理服务定义，支持PDF、DOCX、EPUB、HTML、Markdown与纯文本，格式按内容识别
 type PdfProcessorServer interface {
-	// 流式上传PDF并返回解析文本
+	// 流式上传文档并返回解析文本
 	ExtractText(grpc.ClientStreamingServer[PdfRequest, PdfResponse]) error
+	// 流式上传文档，解析过程中逐页返回文本与进度（EPUB按章节，其余格式整篇为一页）
+	ExtractTextStream(grpc.BidiStreamingServer[PdfRequest, ExtractEvent]) error
 	mustEmbedUnimplementedPdfProcessorServer()
 }
 
@@ -74,6 +92,9 @@ type UnimplementedPdfProcessorServer struct{}
 func (UnimplementedPdfProcessorServer) ExtractText(grpc.ClientStreamingServer[PdfRequest, PdfResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method ExtractText not implemented")
 }
+func (UnimplementedPdfProcessorServer) ExtractTextStream(grpc.BidiStreamingServer[PdfRequest, ExtractEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method ExtractTextStream not implemented")
+}
 func (UnimplementedPdfProcessorServer) mustEmbedUnimplementedPdfProcessorServer() {}
 func (UnimplementedPdfProcessorServer) testEmbeddedByValue()                      {}
 
@@ -102,6 +123,13 @@ func _PdfProcessor_ExtractText_Handler(srv interface{}, stream grpc.ServerStream
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PdfProcessor_ExtractTextServer = grpc.ClientStreamingServer[PdfRequest, PdfResponse]
 
+func _PdfProcessor_ExtractTextStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PdfProcessorServer).ExtractTextStream(&grpc.GenericServerStream[PdfRequest, ExtractEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfProcessor_ExtractTextStreamServer = grpc.BidiStreamingServer[PdfRequest, ExtractEvent]
+
 // PdfProcessor_ServiceDesc is the grpc.ServiceDesc for PdfProcessor service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +143,12 @@ var PdfProcessor_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _PdfProcessor_ExtractText_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ExtractTextStream",
+			Handler:       _PdfProcessor_ExtractTextStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "mcp.proto",
 }
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"time"
+
+	"github.com/zeromicro/go-zero/zrpc"
+)
+
+type Config struct {
+	zrpc.RpcServerConf
+	Runner RunnerConfig
+}
+
+// RunnerConfig 代码执行限制
+type RunnerConfig struct {
+	GoBin              string        `json:",default=go"`
+	WorkDir            string        `json:",optional"`     // 临时模块所在目录，默认系统临时目录；每次执行使用独立的子目录与编译缓存
+	BuildTimeout       time.Duration `json:",default=60s"`  // 编译缓存不跨任务共享，每次需重新编译标准库
+	BuildMemoryLimitMB int           `json:",default=1024"` // 编译、vet 进程数据段上限
+	RunTimeout         time.Duration `json:",default=10s"`  // 运行/测试默认超时
+	MaxRunTimeout      time.Duration `json:",default=30s"`  // 请求可指定的最大超时
+	MemoryLimitMB      int           `json:",default=256"`  // 进程数据段上限
+	CPUSeconds         int           `json:",default=10"`   // 进程CPU时间上限
+	MaxProcesses       int           `json:",default=512"`  // 每个执行uid的进程与线程数上限（ulimit -u），防止fork炸弹
+	MaxCodeBytes       int           `json:",default=65536"`
+	MaxOutputBytes     int           `json:",default=65536"` // stdout/stderr 各自保留的最大长度
+	MaxConcurrent      int           `json:",default=4"`     // 同时执行的任务数
+	RunUIDBase         int           `json:",optional"`      // 每个执行槽位使用独立的uid/gid（RunUIDBase+槽位序号），需以root运行；为0时共用服务进程的uid，仅限本地开发
+	Isolate            bool          `json:",default=true"`  // 在独立的用户、PID与网络命名空间中运行（需内核支持user namespace）
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ai-gozero-agent/sandbox/internal/runner"
+	"ai-gozero-agent/sandbox/internal/svc"
+	"ai-gozero-agent/sandbox/types/sandbox"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RunGoLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewRunGoLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RunGoLogic {
+	return &RunGoLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 在隔离环境中编译运行Go代码，可选执行 go vet 与 go test
+func (l *RunGoLogic) RunGo(in *sandbox.RunGoRequest) (*sandbox.RunGoResponse, error) {
+	if in.Code == "" {
+		return &sandbox.RunGoResponse{Error: "代码不能为空"}, nil
+	}
+
+	result, err := l.svcCtx.Runner.Run(l.ctx, runner.Request{
+		Code:     in.Code,
+		TestCode: in.TestCode,
+		Vet:      in.Vet,
+		Timeout:  time.Duration(in.TimeoutSeconds) * time.Second,
+	})
+	if errors.Is(err, runner.ErrCodeTooLarge) {
+		return &sandbox.RunGoResponse{Error: "代码超过长度限制"}, nil
+	}
+	if err != nil {
+		l.Errorf("运行代码失败: %v", err)
+		return &sandbox.RunGoResponse{Error: err.Error()}, nil
+	}
+
+	resp := &sandbox.RunGoResponse{
+		ExitCode:    int32(result.ExitCode),
+		Stdout:      result.Stdout,
+		Stderr:      result.Stderr,
+		VetOutput:   result.VetOutput,
+		TimedOut:    result.TimedOut,
+		BuildFailed: result.BuildFailed,
+	}
+	for _, tc := range result.Tests {
+		resp.Tests = append(resp.Tests, &sandbox.TestCase{
+			Name:    tc.Name,
+			Passed:  tc.Passed,
+			Elapsed: tc.Elapsed,
+			Output:  tc.Output,
+		})
+	}
+	return resp, nil
+}
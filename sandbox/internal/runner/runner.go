@@ -0,0 +1,382 @@
+// Package runner 在临时模块中编译运行Go代码：禁用模块下载，编译与运行均限制CPU时间、内存、进程数与输出，
+// 每次执行使用独立的目录、编译缓存与uid，并可在独立的用户、PID与网络命名空间中执行
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"ai-gozero-agent/sandbox/internal/config"
+)
+
+const (
+	goModContent = "module sandbox\n\ngo 1.24\n"
+	killWait     = 2 * time.Second
+	runFileMB    = 10  // 运行阶段单个文件大小上限
+	buildFileMB  = 256 // 编译阶段单个文件大小上限（编译缓存与可执行文件）
+)
+
+var ErrCodeTooLarge = errors.New("code too large")
+
+type Request struct {
+	Code     string
+	TestCode string
+	Vet      bool
+	Timeout  time.Duration // 为0时使用默认值
+}
+
+type TestCase struct {
+	Name    string
+	Passed  bool
+	Elapsed float64
+	Output  string
+}
+
+type Result struct {
+	ExitCode    int
+	Stdout      string
+	Stderr      string
+	VetOutput   string
+	Tests       []TestCase
+	TimedOut    bool
+	BuildFailed bool
+}
+
+type Runner struct {
+	c       config.RunnerConfig
+	goBin   string
+	workDir string
+	build   limits
+	run     limits
+	slots   chan int // 空闲的执行槽位，槽位决定执行使用的uid
+}
+
+// limits 通过 ulimit 施加的资源限制
+type limits struct {
+	cpuSeconds int
+	memoryMB   int
+	fileMB     int
+	processes  int
+}
+
+func New(c config.RunnerConfig) (*Runner, error) {
+	goBin, err := exec.LookPath(c.GoBin)
+	if err != nil {
+		return nil, fmt.Errorf("go toolchain not found: %w", err)
+	}
+
+	workDir := c.WorkDir
+	if workDir == "" {
+		workDir = filepath.Join(os.TempDir(), "sandbox")
+	}
+	if err := os.MkdirAll(workDir, 0o700); err != nil {
+		return nil, err
+	}
+	if c.RunUIDBase > 0 {
+		if os.Geteuid() != 0 {
+			return nil, errors.New("RunUIDBase requires running as root")
+		}
+		// 各次执行以不同uid运行，需能进入工作目录，但不能列出其中的其他执行目录
+		if err := os.Chmod(workDir, 0o711); err != nil {
+			return nil, err
+		}
+	}
+
+	slots := make(chan int, max(c.MaxConcurrent, 1))
+	for i := range cap(slots) {
+		slots <- i
+	}
+
+	return &Runner{
+		c:       c,
+		goBin:   goBin,
+		workDir: workDir,
+		build: limits{
+			cpuSeconds: int(c.BuildTimeout.Seconds()) + 1,
+			memoryMB:   c.BuildMemoryLimitMB,
+			fileMB:     buildFileMB,
+			processes:  c.MaxProcesses,
+		},
+		run: limits{
+			cpuSeconds: c.CPUSeconds,
+			memoryMB:   c.MemoryLimitMB,
+			fileMB:     runFileMB,
+			processes:  c.MaxProcesses,
+		},
+		slots: slots,
+	}, nil
+}
+
+// Run 编译并运行代码；提供测试代码时执行测试，否则作为 main 包运行
+func (r *Runner) Run(ctx context.Context, req Request) (*Result, error) {
+	if len(req.Code)+len(req.TestCode) > r.c.MaxCodeBytes {
+		return nil, ErrCodeTooLarge
+	}
+
+	var slot int
+	select {
+	case slot = <-r.slots:
+		defer func() { r.slots <- slot }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	cred := r.credential(slot)
+
+	// 目录权限为0700且属于本次执行的uid；编译缓存放在本次执行的目录中，用户程序写入的内容不会影响其他任务的编译
+	dir, err := os.MkdirTemp(r.workDir, "run-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	// TMPDIR 不能是模块根目录，否则 go 命令会忽略其中的 go.mod
+	for _, sub := range []string{"tmp", "gocache"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, err
+		}
+	}
+	files := map[string]string{"go.mod": goModContent}
+	if req.TestCode != "" {
+		files["solution.go"] = req.Code
+		files["solution_test.go"] = req.TestCode
+	} else {
+		files["main.go"] = req.Code
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			return nil, err
+		}
+	}
+	if cred != nil {
+		err := filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			return os.Lchown(path, int(cred.Uid), int(cred.Gid))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := req.Timeout
+	if timeout <= 0 {
+		timeout = r.c.RunTimeout
+	}
+	timeout = min(timeout, r.c.MaxRunTimeout)
+
+	result := &Result{}
+	if req.Vet {
+		out := r.exec(ctx, dir, cred, r.c.BuildTimeout, r.build, r.goBin, "vet", ".")
+		result.VetOutput = strings.TrimSpace(out.stdout + out.stderr)
+	}
+
+	// 编译
+	binary := filepath.Join(dir, "prog")
+	buildArgs := []string{"build", "-o", binary, "."}
+	if req.TestCode != "" {
+		buildArgs = []string{"test", "-c", "-o", binary, "."}
+	}
+	build := r.exec(ctx, dir, cred, r.c.BuildTimeout, r.build, r.goBin, buildArgs...)
+	if build.exitCode != 0 {
+		result.BuildFailed = true
+		result.ExitCode = build.exitCode
+		result.TimedOut = build.timedOut
+		result.Stderr = build.stdout + build.stderr
+		return result, nil
+	}
+
+	// 运行
+	if req.TestCode != "" {
+		run := r.exec(ctx, dir, cred, timeout, r.run, r.goBin, "tool", "test2json", "-t", binary, "-test.v=test2json", "-test.count=1")
+		result.ExitCode, result.TimedOut, result.Stderr = run.exitCode, run.timedOut, run.stderr
+		result.Stdout, result.Tests = parseTestEvents(run.stdout)
+		return result, nil
+	}
+	run := r.exec(ctx, dir, cred, timeout, r.run, binary)
+	result.ExitCode, result.TimedOut = run.exitCode, run.timedOut
+	result.Stdout, result.Stderr = run.stdout, run.stderr
+	return result, nil
+}
+
+type execResult struct {
+	stdout   string
+	stderr   string
+	exitCode int
+	timedOut bool
+}
+
+// exec 施加CPU时间、内存、文件大小与进程数限制后执行命令；超时后终止整个进程组，
+// 在独立PID命名空间中执行时命名空间内的全部进程随之终止（包括脱离进程组的进程）
+func (r *Runner) exec(ctx context.Context, dir string, cred *syscall.Credential, timeout time.Duration, lim limits, name string, args ...string) execResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 通过 shell ulimit 设置资源限制后 exec 目标程序；进程数限制在 bash/busybox 中为 -u，在 dash 中为 -p
+	script := fmt.Sprintf("ulimit -t %d && ulimit -d %d && ulimit -f %d && ulimit -n 256 && "+
+		"{ ulimit -u %[4]d || ulimit -p %[4]d; } 2>/dev/null || exit 125; exec \"$0\" \"$@\"",
+		lim.cpuSeconds, lim.memoryMB*1024, lim.fileMB*1024, lim.processes)
+	args = append([]string{"-c", script, name}, args...)
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", args...)
+	cmd.Dir = dir
+	cmd.Env = r.env(dir, lim)
+	cmd.SysProcAttr = r.sysProcAttr(cred)
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = killWait
+
+	stdout := &limitedBuffer{limit: r.c.MaxOutputBytes}
+	stderr := &limitedBuffer{limit: r.c.MaxOutputBytes}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	err := cmd.Run()
+	res := execResult{stdout: stdout.String(), stderr: stderr.String()}
+	res.timedOut = errors.Is(ctx.Err(), context.DeadlineExceeded)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		res.exitCode = exitErr.ExitCode()
+	default:
+		res.exitCode = -1
+		res.stderr += err.Error()
+	}
+	if res.timedOut && res.exitCode == 0 {
+		res.exitCode = -1
+	}
+	return res
+}
+
+func (r *Runner) env(dir string, lim limits) []string {
+	return []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + dir,
+		"TMPDIR=" + filepath.Join(dir, "tmp"),
+		"GOCACHE=" + filepath.Join(dir, "gocache"),
+		"GOPATH=" + filepath.Join(dir, "gopath"),
+		"GOPROXY=off", // 禁止下载依赖
+		"GOFLAGS=-mod=mod",
+		"GOTOOLCHAIN=local",
+		"GOTELEMETRY=off",
+		"CGO_ENABLED=0",
+		"GOMEMLIMIT=" + strconv.Itoa(lim.memoryMB) + "MiB",
+	}
+}
+
+// credential 槽位对应的uid/gid，未配置 RunUIDBase 时返回nil（使用服务进程的uid）
+func (r *Runner) credential(slot int) *syscall.Credential {
+	if r.c.RunUIDBase <= 0 {
+		return nil
+	}
+	id := uint32(r.c.RunUIDBase + slot)
+	return &syscall.Credential{Uid: id, Gid: id, Groups: []uint32{}}
+}
+
+func (r *Runner) sysProcAttr(cred *syscall.Credential) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{Setpgid: true, Credential: cred}
+	if !r.c.Isolate {
+		return attr
+	}
+
+	// 新的网络命名空间中只有未启用的回环网卡；PID命名空间中看不到也无法向外部进程发送信号
+	attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWPID | syscall.CLONE_NEWNET
+	uid, gid := os.Getuid(), os.Getgid()
+	if cred != nil {
+		uid, gid = int(cred.Uid), int(cred.Gid)
+		// 以root写入映射，允许子进程清空附加组后再切换到执行uid
+		attr.GidMappingsEnableSetgroups = true
+	}
+	attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}}
+	attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}}
+	return attr
+}
+
+// testEvent go test -json（test2json）事件
+type testEvent struct {
+	Action  string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// parseTestEvents 解析测试事件，返回非测试输出与各测试结果
+func parseTestEvents(stream string) (string, []TestCase) {
+	var other strings.Builder
+	var tests []TestCase
+	index := make(map[string]int)
+	outputs := make(map[string]*strings.Builder)
+
+	for _, line := range strings.Split(stream, "\n") {
+		var e testEvent
+		if line == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			other.WriteString(line + "\n")
+			continue
+		}
+
+		switch {
+		case e.Test == "":
+			if e.Action == "output" {
+				other.WriteString(e.Output)
+			}
+		case e.Action == "output":
+			if outputs[e.Test] == nil {
+				outputs[e.Test] = &strings.Builder{}
+			}
+			outputs[e.Test].WriteString(e.Output)
+		case e.Action == "pass" || e.Action == "fail" || e.Action == "skip":
+			if _, ok := index[e.Test]; !ok {
+				index[e.Test] = len(tests)
+				tests = append(tests, TestCase{Name: e.Test})
+			}
+			tc := &tests[index[e.Test]]
+			tc.Passed = e.Action != "fail"
+			tc.Elapsed = e.Elapsed
+		}
+	}
+	for i := range tests {
+		if out := outputs[tests[i].Name]; out != nil {
+			tests[i].Output = out.String()
+		}
+	}
+	return other.String(), tests
+}
+
+// limitedBuffer 只保留前 limit 字节的输出
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remain := max(b.limit-b.buf.Len(), 0)
+	if len(p) > remain {
+		b.truncated = true
+	}
+	b.buf.Write(p[:min(len(p), remain)])
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n...(output truncated)"
+	}
+	return b.buf.String()
+}
@@ -0,0 +1,26 @@
+package svc
+
+import (
+	"ai-gozero-agent/sandbox/internal/config"
+	"ai-gozero-agent/sandbox/internal/runner"
+	"log"
+)
+
+type ServiceContext struct {
+	Config config.Config
+	Runner *runner.Runner
+}
+
+func NewServiceContext(c config.Config) *ServiceContext {
+	if c.Runner.RunUIDBase == 0 {
+		log.Println("Runner.RunUIDBase 未配置：所有执行共用服务进程的uid，仅限本地开发")
+	}
+	r, err := runner.New(c.Runner)
+	if err != nil {
+		log.Fatalf("runner.New err: %v", err)
+	}
+	return &ServiceContext{
+		Config: c,
+		Runner: r,
+	}
+}
@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.0
+// source: sandbox.proto
+
+package sandbox
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CodeRunner_RunGo_FullMethodName = "/sandbox.CodeRunner/RunGo"
+)
+
+// CodeRunnerClient is the client API for CodeRunner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Go代码执行服务定义
+type CodeRunnerClient interface {
+	// 在隔离环境中编译运行Go代码，可选执行 go vet 与 go test
+	RunGo(ctx context.Context, in *RunGoRequest, opts ...grpc.CallOption) (*RunGoResponse, error)
+}
+
+type codeRunnerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCodeRunnerClient(cc grpc.ClientConnInterface) CodeRunnerClient {
+	return &codeRunnerClient{cc}
+}
+
+func (c *codeRunnerClient) RunGo(ctx context.Context, in *RunGoRequest, opts ...grpc.CallOption) (*RunGoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunGoResponse)
+	err := c.cc.Invoke(ctx, CodeRunner_RunGo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CodeRunnerServer is the server API for CodeRunner service.
+// All implementations must embed UnimplementedCodeRunnerServer
+// for forward compatibility.
+//
+// Go代码执行服务定义
+type CodeRunnerServer interface {
+	// 在隔离环境中编译运行Go代码，可选执行 go vet 与 go test
+	RunGo(context.Context, *RunGoRequest) (*RunGoResponse, error)
+	mustEmbedUnimplementedCodeRunnerServer()
+}
+
+// UnimplementedCodeRunnerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCodeRunnerServer struct{}
+
+func (UnimplementedCodeRunnerServer) RunGo(context.Context, *RunGoRequest) (*RunGoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunGo not implemented")
+}
+func (UnimplementedCodeRunnerServer) mustEmbedUnimplementedCodeRunnerServer() {}
+func (UnimplementedCodeRunnerServer) testEmbeddedByValue()                    {}
+
+// UnsafeCodeRunnerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CodeRunnerServer will
+// result in compilation errors.
+type UnsafeCodeRunnerServer interface {
+	mustEmbedUnimplementedCodeRunnerServer()
+}
+
+func RegisterCodeRunnerServer(s grpc.ServiceRegistrar, srv CodeRunnerServer) {
+	// If the following call pancis, it indicates UnimplementedCodeRunnerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CodeRunner_ServiceDesc, srv)
+}
+
+func _CodeRunner_RunGo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunGoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CodeRunnerServer).RunGo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CodeRunner_RunGo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CodeRunnerServer).RunGo(ctx, req.(*RunGoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CodeRunner_ServiceDesc is the grpc.ServiceDesc for CodeRunner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CodeRunner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sandbox.CodeRunner",
+	HandlerType: (*CodeRunnerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RunGo",
+			Handler:    _CodeRunner_RunGo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sandbox.proto",
+}